@@ -1,7 +1,9 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 
@@ -17,14 +19,14 @@ type API struct {
 	requester   *request.Requester
 }
 
-func NewAPI(token string) *API {
-	return NewAPIWithClient(token, http.DefaultClient)
+func NewAPI(token string, opts ...request.Option) *API {
+	return NewAPIWithClient(token, http.DefaultClient, opts...)
 }
 
-func NewAPIWithClient(token string, client *http.Client) *API {
+func NewAPIWithClient(token string, client *http.Client, opts ...request.Option) *API {
 	return &API{
 		subscribers: events.NewContainer(),
-		requester:   request.NewRequesterWithClient(token, client),
+		requester:   request.NewRequesterWithClient(token, client, opts...),
 	}
 }
 
@@ -42,30 +44,39 @@ func NewAPIWithClient(token string, client *http.Client) *API {
 //
 // NEW! If you're having any trouble setting up webhooks, please check out this
 // [amazing guide to Webhooks](https://core.telegram.org/bots/webhooks).
-func (b *API) SetWebhook(request models.WebhookRequest) error {
-	var err error
-	if request.Certificate != "" {
-		_, err = b.requester.MultipartRequest("setWebhook", request)
-	} else {
-		_, err = b.requester.JSONRequest("setWebhook", request)
-	}
-
-	return err
+func (b *API) SetWebhook(ctx context.Context, request models.WebhookRequest) error {
+	return b.call(ctx, "setWebhook", request, nil)
 }
 
+// WebhookHandler Emits the update delivered by the webhook request to the subscribers. The request isn't verified,
+// use the webhook package to check the secret token and to limit the body size. If no subscriber accepts the update,
+// e.g. the bot hasn't subscribed yet or has stopped, it replies 503 Service Unavailable, so that Telegram delivers
+// the update again.
 func (b *API) WebhookHandler(w http.ResponseWriter, r *http.Request) {
-	body, _ := ioutil.ReadAll(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.WithError(err).Error("unable to read webhook request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
 	log.WithField("body", string(body)).Trace("incoming request")
 
 	var update models.Update
-	err := json.Unmarshal(body, &update)
+	err = json.Unmarshal(body, &update)
 	if err != nil {
+		log.WithError(err).Error("unable to decode webhook request")
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	b.subscribers.Emit(update)
+	// Telegram delivers the update again later if it isn't accepted now
+	if !b.subscribers.Emit(update) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
 
 // GetUpdates Use this method to receive incoming updates using long polling [wiki](https://en.wikipedia.org/wiki/Push_technology#Long_polling).
@@ -74,14 +85,9 @@ func (b *API) WebhookHandler(w http.ResponseWriter, r *http.Request) {
 // Notes
 // 1. This method will not work if an outgoing webhook is set up.
 // 2. In order to avoid getting duplicate updates, recalculate offset after each server response.
-func (b *API) GetUpdates(request models.UpdateRequest) ([]models.Update, error) {
-	data, err := b.requester.JSONRequest("getUpdates", request)
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) GetUpdates(ctx context.Context, request models.UpdateRequest) ([]models.Update, error) {
 	var updates []models.Update
-	err = json.Unmarshal(data, &updates)
+	err := b.call(ctx, "getUpdates", request, &updates)
 	if err != nil {
 		return nil, err
 	}
@@ -91,14 +97,9 @@ func (b *API) GetUpdates(request models.UpdateRequest) ([]models.Update, error)
 
 // GetWebhookInfo Use this method to get current webhook status. Requires no parameters. On success, returns a WebhookInfo object.
 // If the bot is using getUpdates, will return an object with the url field empty.
-func (b *API) GetWebhookInfo() (*models.WebhookInfo, error) {
-	data, err := b.requester.JSONRequest("getWebhookInfo", []byte(""))
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) GetWebhookInfo(ctx context.Context) (*models.WebhookInfo, error) {
 	var webhook models.WebhookInfo
-	err = json.Unmarshal(data, &webhook)
+	err := b.call(ctx, "getWebhookInfo", nil, &webhook)
 	if err != nil {
 		return nil, err
 	}
@@ -108,22 +109,15 @@ func (b *API) GetWebhookInfo() (*models.WebhookInfo, error) {
 
 // DeleteWebhook Use this method to remove webhook integration if you decide to switch back to getUpdates. Returns True on success.
 // Requires no parameters.
-func (b *API) DeleteWebhook() error {
-	_, err := b.requester.JSONRequest("deleteWebhook", []byte(""))
-
-	return err
+func (b *API) DeleteWebhook(ctx context.Context) error {
+	return b.call(ctx, "deleteWebhook", nil, nil)
 }
 
 // GetMe A simple method for testing your bot's auth token. Requires no parameters. Returns basic information about
 // the bot in form of a User object.
-func (b *API) GetMe() (*models.User, error) {
-	data, err := b.requester.JSONRequest("getMe", []byte(""))
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) GetMe(ctx context.Context) (*models.User, error) {
 	var user models.User
-	err = json.Unmarshal(data, &user)
+	err := b.call(ctx, "getMe", nil, &user)
 	if err != nil {
 		return nil, err
 	}
@@ -132,71 +126,66 @@ func (b *API) GetMe() (*models.User, error) {
 }
 
 // ForwardMessage Use this method to forward messages of any kind.
-func (b *API) ForwardMessage(request models.ForwardMessageRequest) (*models.Message, error) {
-	return b.sendMessage("forwardMessage", request)
+func (b *API) ForwardMessage(ctx context.Context, request models.ForwardMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "forwardMessage", request)
 }
 
 // SendMessage Use this method to send text messages. On success, the sent Message is returned.
-func (b *API) SendMessage(request models.MessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendMessage", request)
+func (b *API) SendMessage(ctx context.Context, request models.MessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendMessage", request)
 }
 
 // SendPhoto Use this method to send photos.
-func (b *API) SendPhoto(request models.PhotoMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendPhoto", request)
+func (b *API) SendPhoto(ctx context.Context, request models.PhotoMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendPhoto", request)
 }
 
 // SendAudio Use this method to send audio files, if you want Telegram clients to display them in the music player.
 // Your audio must be in the .MP3 or .M4A format. On success, the sent Message is returned. Bots can currently
 // send audio files of up to 50 MB in size, this limit may be changed in the future.
-func (b *API) SendAudio(request models.AudioMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendAudio", request)
+func (b *API) SendAudio(ctx context.Context, request models.AudioMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendAudio", request)
 }
 
 // SendDocument Use this method to send general files. On success, the sent Message is returned. Bots can currently send files
 // of any type of up to 50 MB in size, this limit may be changed in the future.
-func (b *API) SendDocument(request models.DocumentMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendDocument", request)
+func (b *API) SendDocument(ctx context.Context, request models.DocumentMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendDocument", request)
 }
 
 // SendVideo Use this method to send video files, Telegram clients support mp4 videos (other formats may be sent as Document).
 // On success, the sent Message is returned. Bots can currently send video files of up to 50 MB in size,
 // this limit may be changed in the future.
-func (b *API) SendVideo(request models.VideoMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendVideo", request)
+func (b *API) SendVideo(ctx context.Context, request models.VideoMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendVideo", request)
 }
 
 // SendAnimation Use this method to send animation files (GIF or H.264/MPEG-4 AVC video without sound). On success, the sent
 // Message is returned. Bots can currently send animation files of up to 50 MB in size, this limit may be changed
 // in the future.
-func (b *API) SendAnimation(request models.AnimationMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendAnimation", request)
+func (b *API) SendAnimation(ctx context.Context, request models.AnimationMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendAnimation", request)
 }
 
 // SendVoice Use this method to send audio files, if you want Telegram clients to display the file as a playable voice message.
 // For this to work, your audio must be in an .OGG file encoded with OPUS (other formats may be sent as Audio
 // or Document). On success, the sent Message is returned. Bots can currently send voice messages of up to 50 MB
 // 	in size, this limit may be changed in the future.
-func (b *API) SendVoice(request models.VoiceMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendVoice", request)
+func (b *API) SendVoice(ctx context.Context, request models.VoiceMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendVoice", request)
 }
 
 // SendVideoNote As of v.4.0, Telegram clients support rounded square mp4 videos of up to 1 minute long.
 // Use this method to send video messages. On success, the sent Message is returned.
-func (b *API) SendVideoNote(request models.VideoNoteMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendVideoNote", request)
+func (b *API) SendVideoNote(ctx context.Context, request models.VideoNoteMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendVideoNote", request)
 }
 
 // SendMediaGroup Use this method to send a group of photos or videos as an album. On success,
 // an array of the sent Messages is returned.
-func (b *API) SendMediaGroup(request models.MediaGroupMessageRequest) ([]models.Message, error) {
-	data, err := b.requester.JSONRequest("sendMediaGroup", request)
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) SendMediaGroup(ctx context.Context, request models.MediaGroupMessageRequest) ([]models.Message, error) {
 	var messages []models.Message
-	err = json.Unmarshal(data, &messages)
+	err := b.call(ctx, "sendMediaGroup", request, &messages)
 	if err != nil {
 		return nil, err
 	}
@@ -205,52 +194,43 @@ func (b *API) SendMediaGroup(request models.MediaGroupMessageRequest) ([]models.
 }
 
 // SendLocation Use this method to send point on the map. On success, the sent Message is returned.
-func (b *API) SendLocation(request models.LocationMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendLocation", request)
+func (b *API) SendLocation(ctx context.Context, request models.LocationMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendLocation", request)
 }
 
 // EditMessageLiveLocation Use this method to edit live location messages. A location can be edited until its live_period expires or
 // editing is explicitly disabled by a call to stopMessageLiveLocation. On success, if the edited message was sent
 // by the bot, the edited Message is returned, otherwise True is returned.
-func (b *API) EditMessageLiveLocation(request models.EditMessageLiveLocation) (bool, error) {
-	_, err := b.requester.JSONRequest("editMessageLiveLocation", request)
-
-	return err == nil, err
+func (b *API) EditMessageLiveLocation(ctx context.Context, request models.EditMessageLiveLocation) (*models.EditMessageResult, error) {
+	return b.editMessage(ctx, "editMessageLiveLocation", request)
 }
 
 // StopMessageLiveLocation Use this method to stop updating a live location message before live_period expires. On success, if the message
 // was sent by the bot, the sent Message is returned, otherwise True is returned.
-func (b *API) StopMessageLiveLocation(request models.StopMessageLiveLocation) (bool, error) {
-	_, err := b.requester.JSONRequest("stopMessageLiveLocation", request)
-
-	return err == nil, err
+func (b *API) StopMessageLiveLocation(ctx context.Context, request models.StopMessageLiveLocation) (*models.EditMessageResult, error) {
+	return b.editMessage(ctx, "stopMessageLiveLocation", request)
 }
 
 // SendVenue Use this method to send information about a venue. On success, the sent Message is returned.
-func (b *API) SendVenue(request models.VenueMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendVenue", request)
+func (b *API) SendVenue(ctx context.Context, request models.VenueMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendVenue", request)
 }
 
 // SendContact Use this method to send phone contacts. On success, the sent Message is returned.
-func (b *API) SendContact(request models.ContactMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendContact", request)
+func (b *API) SendContact(ctx context.Context, request models.ContactMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendContact", request)
 }
 
 // SendPoll Use this method to send a native poll. On success, the sent Message is returned.
-func (b *API) SendPoll(request models.PollMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendPoll", request)
+func (b *API) SendPoll(ctx context.Context, request models.PollMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendPoll", request)
 }
 
 // StopPoll Use this method to stop a poll which was sent by the bot. On success, the stopped Poll with the final results
 // is returned.
-func (b *API) StopPoll(request models.PollMessageRequest) (*models.Poll, error) {
-	data, err := b.requester.JSONRequest("stopPoll", request)
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) StopPoll(ctx context.Context, request models.StopPollRequest) (*models.Poll, error) {
 	var result models.Poll
-	err = json.Unmarshal(data, &result)
+	err := b.call(ctx, "stopPoll", request, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -261,8 +241,8 @@ func (b *API) StopPoll(request models.PollMessageRequest) (*models.Poll, error)
 // SendDice Use this method to send a dice, which will have a random value from 1 to 6. On success, the sent Message is returned.
 // (Yes, we're aware of the “proper” singular of die. But it's awkward, and we decided to help it change.
 // One dice at a time!)
-func (b *API) SendDice(request models.DiceMessageRequest) (*models.Message, error) {
-	return b.sendMessage("sendDice", request)
+func (b *API) SendDice(ctx context.Context, request models.DiceMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendDice", request)
 }
 
 // DeleteMessage Use this method to delete a message, including service messages, with the following limitations:
@@ -277,13 +257,11 @@ func (b *API) SendDice(request models.DiceMessageRequest) (*models.Message, erro
 //
 // chatID    - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 // messageID - Identifier of the message to delete
-func (b *API) DeleteMessage(chatID string, messageID int64) (bool, error) {
-	_, err := b.requester.JSONRequest("deleteMessage", map[string]interface{}{
+func (b *API) DeleteMessage(ctx context.Context, chatID string, messageID int64) error {
+	return b.call(ctx, "deleteMessage", map[string]interface{}{
 		"chat_id":    chatID,
 		"message_id": messageID,
-	})
-
-	return err == nil, err
+	}, nil)
 }
 
 // SendChatAction Use this method when you need to tell the user that something is happening on the bot's side.
@@ -293,24 +271,17 @@ func (b *API) DeleteMessage(chatID string, messageID int64) (bool, error) {
 //          along the lines of “Retrieving image, please wait…”, the bot may use sendChatAction with
 //          action = upload_photo. The user will see a “sending photo” status for the bot.
 // We only recommend using this method when a response from the bot will take a noticeable amount of time to arrive.
-func (b *API) SendChatAction(chatID string, action models.ChatAction) (bool, error) {
-	_, err := b.requester.JSONRequest("sendChatAction", map[string]interface{}{
+func (b *API) SendChatAction(ctx context.Context, chatID string, action models.ChatAction) error {
+	return b.call(ctx, "sendChatAction", map[string]interface{}{
 		"chat_id": chatID,
 		"action":  action,
-	})
-
-	return err == nil, err
+	}, nil)
 }
 
 // GetUserProfilePhotos Use this method to get a list of profile pictures for a user. Returns a UserProfilePhotos object.
-func (b *API) GetUserProfilePhotos(request models.UserProfilePhotosRequest) (*models.UserProfilePhotos, error) {
-	data, err := b.requester.JSONRequest("getUserProfilePhotos", request)
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) GetUserProfilePhotos(ctx context.Context, request models.UserProfilePhotosRequest) (*models.UserProfilePhotos, error) {
 	var response models.UserProfilePhotos
-	err = json.Unmarshal(data, &response)
+	err := b.call(ctx, "getUserProfilePhotos", request, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -325,15 +296,40 @@ func (b *API) GetUserProfilePhotos(request models.UserProfilePhotosRequest) (*mo
 // requested by calling getFile again.
 // Note: This function may not preserve the original file name and MIME type. You should save the file's MIME type
 //       and name (if available) when the File object is received.
-func (b *API) GetFile(fileID string) (string, error) {
-	data, err := b.requester.JSONRequest("getFile", map[string]interface{}{
+func (b *API) GetFile(ctx context.Context, fileID string) (*models.File, error) {
+	var file models.File
+	err := b.call(ctx, "getFile", map[string]interface{}{
 		"file_id": fileID,
-	})
+	}, &file)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(data), nil
+	return &file, nil
+}
+
+// FileURL Returns the link to download the file received from GetFile. The link contains the bot's token,
+// so it must not be exposed to users.
+func (b *API) FileURL(file *models.File) string {
+	return b.requester.FileURL(file.FilePath)
+}
+
+// DownloadFile Streams the content of the file into w. Files larger than 20MB can't be downloaded
+// by bots, request.ErrFileTooLarge is returned for them. The limit doesn't apply to files read locally
+// in the local mode, see request.WithLocalMode.
+func (b *API) DownloadFile(ctx context.Context, fileID string, w io.Writer) error {
+	file, err := b.GetFile(ctx, fileID)
+	if err != nil {
+		return err
+	}
+
+	if !b.requester.IsLocalFile(file.FilePath) && file.FileSize > request.MaxDownloadFileSize {
+		return request.ErrFileTooLarge
+	}
+
+	_, err = b.requester.Download(ctx, file.FilePath, w)
+
+	return err
 }
 
 // KickChatMember Use this method to kick a user from a group, a supergroup or a channel. In the case of supergroups and channels,
@@ -346,7 +342,7 @@ func (b *API) GetFile(fileID string) (string, error) {
 // userID         - Unique identifier of the target user
 // untilTimestamp - Date when the user will be unbanned, unix time. If user is banned for more than 366 days or less
 //                  than 30 seconds from the current time they are considered to be banned forever
-func (b *API) KickChatMember(chatID string, userID int64, untilTimestamp ...int64) (bool, error) {
+func (b *API) KickChatMember(ctx context.Context, chatID string, userID int64, untilTimestamp ...int64) error {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 		"user_id": userID,
@@ -356,12 +352,7 @@ func (b *API) KickChatMember(chatID string, userID int64, untilTimestamp ...int6
 		r["until_date"] = untilTimestamp[0]
 	}
 
-	_, err := b.requester.JSONRequest("kickChatMember", r)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+	return b.call(ctx, "kickChatMember", r, nil)
 }
 
 // UnbanChatMember Use this method to unban a previously kicked user in a supergroup or channel. The user will not return
@@ -371,42 +362,27 @@ func (b *API) KickChatMember(chatID string, userID int64, untilTimestamp ...int6
 // chatID         - Unique identifier for the target group or username of the target supergroup
 //                  or channel (in the format @channelusername)
 // userID         - Unique identifier of the target user
-func (b *API) UnbanChatMember(chatID string, userID int64) (bool, error) {
+func (b *API) UnbanChatMember(ctx context.Context, chatID string, userID int64) error {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 		"user_id": userID,
 	}
 
-	_, err := b.requester.JSONRequest("unbanChatMember", r)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+	return b.call(ctx, "unbanChatMember", r, nil)
 }
 
 // RestrictChatMember Use this method to restrict a user in a supergroup. The bot must be an administrator in the supergroup for this
 // to work and must have the appropriate admin rights. Pass True for all permissions to lift restrictions from a user.
 // Returns True on success.
-func (b *API) RestrictChatMember(request models.ChatMemberRestrictionsRequest) (bool, error) {
-	_, err := b.requester.JSONRequest("restrictChatMember", request)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+func (b *API) RestrictChatMember(ctx context.Context, request models.ChatMemberRestrictionsRequest) error {
+	return b.call(ctx, "restrictChatMember", request, nil)
 }
 
 // PromoteChatMember Use this method to promote or demote a user in a supergroup or a channel. The bot must be an administrator
 // in the chat for this to work and must have the appropriate admin rights. Pass False for all boolean parameters
 // to demote a user. Returns True on success.
-func (b *API) PromoteChatMember(request models.ChatMemberPromotionRequest) (bool, error) {
-	_, err := b.requester.JSONRequest("promoteChatMember", request)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+func (b *API) PromoteChatMember(ctx context.Context, request models.ChatMemberPromotionRequest) error {
+	return b.call(ctx, "promoteChatMember", request, nil)
 }
 
 // SetChatAdministratorCustomTitle Use this method to set a custom title for an administrator in a supergroup promoted by the bot.
@@ -415,19 +391,14 @@ func (b *API) PromoteChatMember(request models.ChatMemberPromotionRequest) (bool
 // chatID - Unique identifier for the target chat or username of the target supergroup (in the format @supergroupusername)
 // userID - Unique identifier of the target user
 // title  - New custom title for the administrator; 0-16 characters, emoji are not allowed
-func (b *API) SetChatAdministratorCustomTitle(chatID string, userID int64, title string) (bool, error) {
+func (b *API) SetChatAdministratorCustomTitle(ctx context.Context, chatID string, userID int64, title string) error {
 	r := map[string]interface{}{
 		"chat_id":      chatID,
 		"user_id":      userID,
 		"custom_title": title,
 	}
 
-	_, err := b.requester.JSONRequest("setChatAdministratorCustomTitle", r)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+	return b.call(ctx, "setChatAdministratorCustomTitle", r, nil)
 }
 
 // SetChatPermissions Use this method to set default chat permissions for all members. The bot must be an administrator in the group
@@ -436,18 +407,13 @@ func (b *API) SetChatAdministratorCustomTitle(chatID string, userID int64, title
 // chatID      - Unique identifier for the target chat or username of the target supergroup
 //               (in the format @supergroupusername)
 // permissions - New default chat permissions
-func (b *API) SetChatPermissions(chatID string, permissions models.ChatPermissions) (bool, error) {
+func (b *API) SetChatPermissions(ctx context.Context, chatID string, permissions models.ChatPermissions) error {
 	r := map[string]interface{}{
 		"chat_id":     chatID,
 		"permissions": permissions,
 	}
 
-	_, err := b.requester.JSONRequest("setChatPermissions", r)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+	return b.call(ctx, "setChatPermissions", r, nil)
 }
 
 // ExportChatInviteLink Use this method to generate a new invite link for a chat; any previously generated link is revoked. The bot
@@ -459,38 +425,35 @@ func (b *API) SetChatPermissions(chatID string, permissions models.ChatPermissio
 //       its own link using exportChatInviteLink – after this the link will become available to the bot
 //       via the getChat method. If your bot needs to generate a new invite link replacing its previous one,
 //       use exportChatInviteLink again.
-func (b *API) ExportChatInviteLink(chatID string) (string, error) {
+func (b *API) ExportChatInviteLink(ctx context.Context, chatID string) (string, error) {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	data, err := b.requester.JSONRequest("exportChatInviteLink", r)
+	var link string
+	err := b.call(ctx, "exportChatInviteLink", r, &link)
 	if err != nil {
 		return "", err
 	}
 
-	return string(data), nil
+	return link, nil
 }
 
 // SetChatPhoto Use this method to set a new profile photo for the chat. Photos can't be changed for private chats.
 // The bot must be an administrator in the chat for this to work and must have the appropriate admin rights.
 // Returns True on success.
-func (b *API) SetChatPhoto(request models.ChatSetPhotoRequest) (bool, error) {
-	_, err := b.requester.JSONRequest("setChatPhoto", request)
-
-	return err == nil, err
+func (b *API) SetChatPhoto(ctx context.Context, request models.ChatSetPhotoRequest) error {
+	return b.call(ctx, "setChatPhoto", request, nil)
 }
 
 // DeleteChatPhoto Use this method to delete a chat photo. Photos can't be changed for private chats. The bot must be an administrator
 // in the chat for this to work and must have the appropriate admin rights. Returns True on success.
-func (b *API) DeleteChatPhoto(chatID string) (bool, error) {
+func (b *API) DeleteChatPhoto(ctx context.Context, chatID string) error {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	_, err := b.requester.JSONRequest("deleteChatPhoto", r)
-
-	return err == nil, err
+	return b.call(ctx, "deleteChatPhoto", r, nil)
 }
 
 // SetChatTitle Use this method to change the title of a chat. Titles can't be changed for private chats. The bot must be
@@ -499,15 +462,13 @@ func (b *API) DeleteChatPhoto(chatID string) (bool, error) {
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 // title  - New chat title, 1-255 characters
-func (b *API) SetChatTitle(chatID, title string) (bool, error) {
+func (b *API) SetChatTitle(ctx context.Context, chatID, title string) error {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 		"title":   title,
 	}
 
-	_, err := b.requester.JSONRequest("setChatTitle", r)
-
-	return err == nil, err
+	return b.call(ctx, "setChatTitle", r, nil)
 }
 
 // SetChatDescription Use this method to change the description of a group, a supergroup or a channel. The bot must be an administrator
@@ -516,15 +477,13 @@ func (b *API) SetChatTitle(chatID, title string) (bool, error) {
 // chatID       - Unique identifier for the target chat or username of the target channel
 //                (in the format @channelusername)
 // description  - New chat description, 1-255 characters
-func (b *API) SetChatDescription(chatID, description string) (bool, error) {
+func (b *API) SetChatDescription(ctx context.Context, chatID, description string) error {
 	r := map[string]interface{}{
 		"chat_id":     chatID,
 		"description": description,
 	}
 
-	_, err := b.requester.JSONRequest("setChatDescription", r)
-
-	return err == nil, err
+	return b.call(ctx, "setChatDescription", r, nil)
 }
 
 // PinChatMessage Use this method to pin a message in a group, a supergroup, or a channel. The bot must be an administrator in the chat
@@ -535,7 +494,7 @@ func (b *API) SetChatDescription(chatID, description string) (bool, error) {
 //                       (in the format @channelusername)
 // messageID           - Identifier of a message to pin
 // disableNotification - Pass True, if it is not necessary to send a notification to all chat members about the new pinned message. Notifications are always disabled in channels.
-func (b *API) PinChatMessage(chatID string, messageID int64, disableNotification ...bool) (bool, error) {
+func (b *API) PinChatMessage(ctx context.Context, chatID string, messageID int64, disableNotification ...bool) error {
 	r := map[string]interface{}{
 		"chat_id":    chatID,
 		"message_id": messageID,
@@ -545,9 +504,7 @@ func (b *API) PinChatMessage(chatID string, messageID int64, disableNotification
 		r["disable_notification"] = disableNotification[0]
 	}
 
-	_, err := b.requester.JSONRequest("pinChatMessage", r)
-
-	return err == nil, err
+	return b.call(ctx, "pinChatMessage", r, nil)
 }
 
 // UnpinChatMessage Use this method to unpin a message in a group, a supergroup, or a channel. The bot must be an administrator
@@ -555,50 +512,41 @@ func (b *API) PinChatMessage(chatID string, messageID int64, disableNotification
 // or ‘can_edit_messages’ admin right in the channel. Returns True on success.
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-func (b *API) UnpinChatMessage(chatID string) (bool, error) {
+func (b *API) UnpinChatMessage(ctx context.Context, chatID string) error {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	_, err := b.requester.JSONRequest("unpinChatMessage", r)
-
-	return err == nil, err
+	return b.call(ctx, "unpinChatMessage", r, nil)
 }
 
 // LeaveChat Use this method for your bot to leave a group, supergroup or channel. Returns True on success.
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-func (b *API) LeaveChat(chatID string) (bool, error) {
+func (b *API) LeaveChat(ctx context.Context, chatID string) error {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	_, err := b.requester.JSONRequest("leaveChat", r)
-
-	return err == nil, err
+	return b.call(ctx, "leaveChat", r, nil)
 }
 
 // GetChat Use this method to get up to date information about the chat (current name of the user for one-on-one conversations,
 // current username of a user, group or channel, etc.). Returns a Chat object on success.
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-func (b *API) GetChat(chatID string) (*models.Chat, error) {
+func (b *API) GetChat(ctx context.Context, chatID string) (*models.Chat, error) {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	data, err := b.requester.JSONRequest("getChat", r)
-	if err != nil {
-		return nil, err
-	}
-
 	var chat models.Chat
-	err = json.Unmarshal(data, &chat)
+	err := b.call(ctx, "getChat", r, &chat)
 	if err != nil {
 		return nil, err
 	}
 
-	return &chat, err
+	return &chat, nil
 }
 
 // GetChatAdministrators Use this method to get a list of administrators in a chat. On success, returns an Array of ChatMember objects that
@@ -606,69 +554,54 @@ func (b *API) GetChat(chatID string) (*models.Chat, error) {
 // no administrators were appointed, only the creator will be returned.
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-func (b *API) GetChatAdministrators(chatID string) ([]string, error) {
+func (b *API) GetChatAdministrators(ctx context.Context, chatID string) ([]models.ChatMember, error) {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	data, err := b.requester.JSONRequest("getChat", r)
+	var response []models.ChatMember
+	err := b.call(ctx, "getChatAdministrators", r, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	var response []string
-	err = json.Unmarshal(data, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return response, err
+	return response, nil
 }
 
 // GetChatMembersCount Use this method to get the number of members in a chat. Returns Int on success.
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-func (b *API) GetChatMembersCount(chatID string) (int, error) {
+func (b *API) GetChatMembersCount(ctx context.Context, chatID string) (int, error) {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	data, err := b.requester.JSONRequest("getChat", r)
-	if err != nil {
-		return 0, err
-	}
-
 	var response int
-	err = json.Unmarshal(data, &response)
+	err := b.call(ctx, "getChatMembersCount", r, &response)
 	if err != nil {
 		return 0, err
 	}
 
-	return response, err
+	return response, nil
 }
 
 // GetChatMember Use this method to get information about a member of a chat. Returns a ChatMember object on success.
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 // userID - Unique identifier of the target user
-func (b *API) GetChatMember(chatID string, userID int64) (*models.ChatMember, error) {
+func (b *API) GetChatMember(ctx context.Context, chatID string, userID int64) (*models.ChatMember, error) {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 		"user_id": userID,
 	}
 
-	data, err := b.requester.JSONRequest("getChatMember", r)
-	if err != nil {
-		return nil, err
-	}
-
 	var response models.ChatMember
-	err = json.Unmarshal(data, &response)
+	err := b.call(ctx, "getChatMember", r, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	return &response, err
+	return &response, nil
 }
 
 // SetChatStickerSet Use this method to set a new group sticker set for a supergroup. The bot must be an administrator in the chat
@@ -677,15 +610,13 @@ func (b *API) GetChatMember(chatID string, userID int64) (*models.ChatMember, er
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
 // userID - Unique identifier of the target user
-func (b *API) SetChatStickerSet(chatID, name string) (bool, error) {
+func (b *API) SetChatStickerSet(ctx context.Context, chatID, name string) error {
 	r := map[string]interface{}{
 		"chat_id":          chatID,
 		"sticker_set_name": name,
 	}
 
-	_, err := b.requester.JSONRequest("setChatStickerSet", r)
-
-	return err == nil, err
+	return b.call(ctx, "setChatStickerSet", r, nil)
 }
 
 // DeleteChatStickerSet Use this method to delete a group sticker set from a supergroup. The bot must be an administrator in the chat
@@ -693,14 +624,12 @@ func (b *API) SetChatStickerSet(chatID, name string) (bool, error) {
 // in getChat requests to check if the bot can use this method. Returns True on success.
 //
 // chatID - Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-func (b *API) DeleteChatStickerSet(chatID string) (bool, error) {
+func (b *API) DeleteChatStickerSet(ctx context.Context, chatID string) error {
 	r := map[string]interface{}{
 		"chat_id": chatID,
 	}
 
-	_, err := b.requester.JSONRequest("deleteChatStickerSet", r)
-
-	return err == nil, err
+	return b.call(ctx, "deleteChatStickerSet", r, nil)
 }
 
 // AnswerCallbackQuery Use this method to send answers to callback queries sent from inline keyboards. The answer will be displayed
@@ -709,31 +638,31 @@ func (b *API) DeleteChatStickerSet(chatID string) (bool, error) {
 // Alternatively, the user can be redirected to the specified Game URL. For this option to work, you must first create
 // a game for your bot via @Botfather and accept the terms. Otherwise, you may use links like t.me/your_bot?start=XXXX
 // that open your bot with a parameter.
-func (b *API) AnswerCallbackQuery(request models.AnswerCallbackQuery) (bool, error) {
-	_, err := b.requester.JSONRequest("answerCallbackQuery", request)
+func (b *API) AnswerCallbackQuery(ctx context.Context, request models.AnswerCallbackQuery) error {
+	return b.call(ctx, "answerCallbackQuery", request, nil)
+}
 
-	return err == nil, err
+// AnswerInlineQuery Use this method to send answers to an inline query. On success, True is returned.
+// No more than 50 results per query are allowed. Use helpers.InlineResultsBuilder to assign identifiers
+// to the results and paginate them.
+func (b *API) AnswerInlineQuery(ctx context.Context, request models.AnswerInlineQuery) error {
+	return b.call(ctx, "answerInlineQuery", request, nil)
 }
 
 // SetMyCommands Use this method to change the list of the bot's commands. Returns True on success.
 // commands - A list of bot commands to be set as the list of the bot's commands.
 //            At most 100 commands can be specified.
-func (b *API) SetMyCommands(request []models.BotCommand) (bool, error) {
-	_, err := b.requester.JSONRequest("setMyCommands", request)
-
-	return err == nil, err
+func (b *API) SetMyCommands(ctx context.Context, commands []models.BotCommand) error {
+	return b.call(ctx, "setMyCommands", map[string]interface{}{
+		"commands": commands,
+	}, nil)
 }
 
 // GetMyCommands Use this method to get the current list of the bot's commands. Requires no parameters.
 // Returns Array of BotCommand on success.
-func (b *API) GetMyCommands() ([]models.BotCommand, error) {
-	data, err := b.requester.JSONRequest("getMyCommands", []byte(""))
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) GetMyCommands(ctx context.Context) ([]models.BotCommand, error) {
 	var response []models.BotCommand
-	err = json.Unmarshal(data, &response)
+	err := b.call(ctx, "getMyCommands", nil, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -743,14 +672,14 @@ func (b *API) GetMyCommands() ([]models.BotCommand, error) {
 
 // EditMessageText Use this method to edit text and game messages. On success, if edited message is sent by the bot, the edited Message
 // is returned, otherwise True is returned.
-func (b *API) EditMessageText(request models.EditMessageTextRequest) (*models.Message, error) {
-	return b.sendMessage("editMessageText", request)
+func (b *API) EditMessageText(ctx context.Context, request models.EditMessageTextRequest) (*models.EditMessageResult, error) {
+	return b.editMessage(ctx, "editMessageText", request)
 }
 
 // EditMessageCaption Use this method to edit captions of messages. On success, if edited message is sent by the bot,
 // the edited Message is returned, otherwise True is returned.
-func (b *API) EditMessageCaption(request models.EditMessageCaptionRequest) (*models.Message, error) {
-	return b.sendMessage("editMessageCaption", request)
+func (b *API) EditMessageCaption(ctx context.Context, request models.EditMessageCaptionRequest) (*models.EditMessageResult, error) {
+	return b.editMessage(ctx, "editMessageCaption", request)
 }
 
 // EditMessageMedia Use this method to edit animation, audio, document, photo, or video messages. If a message is a part of a message
@@ -758,32 +687,27 @@ func (b *API) EditMessageCaption(request models.EditMessageCaptionRequest) (*mod
 // When inline message is edited, new file can't be uploaded. Use previously uploaded file via its file_id or specify
 // a URL. On success, if the edited message was sent by the bot, the edited Message is returned,
 // otherwise True is returned.
-func (b *API) EditMessageMedia(request models.EditMessageMediaRequest) (*models.Message, error) {
-	return b.sendMessage("editMessageMedia", request)
+func (b *API) EditMessageMedia(ctx context.Context, request models.EditMessageMediaRequest) (*models.EditMessageResult, error) {
+	return b.editMessage(ctx, "editMessageMedia", request)
 }
 
 // EditMessageReplyMarkup Use this method to edit only the reply markup of messages. On success, if edited message is sent by the bot,
 // the edited Message is returned, otherwise True is returned.
-func (b *API) EditMessageReplyMarkup(request models.EditMessageReplyMarkupRequest) (*models.Message, error) {
-	return b.sendMessage("editMessageReplyMarkup", request)
+func (b *API) EditMessageReplyMarkup(ctx context.Context, request models.EditMessageReplyMarkupRequest) (*models.EditMessageResult, error) {
+	return b.editMessage(ctx, "editMessageReplyMarkup", request)
 }
 
 // SendSticker Use this method to send static .WEBP or animated .TGS stickers. On success, the sent Message is returned.
-func (b *API) SendSticker(request models.SendStickerRequest) (*models.Message, error) {
-	return b.sendMessage("sendSticker", request)
+func (b *API) SendSticker(ctx context.Context, request models.SendStickerRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendSticker", request)
 }
 
 // GetStickerSet Use this method to get a sticker set. On success, a StickerSet object is returned.
-func (b *API) GetStickerSet(name string) (*models.StickerSet, error) {
-	data, err := b.requester.JSONRequest("setMyCommands", map[string]interface{}{
-		"name": name,
-	})
-	if err != nil {
-		return nil, err
-	}
-
+func (b *API) GetStickerSet(ctx context.Context, name string) (*models.StickerSet, error) {
 	var response models.StickerSet
-	err = json.Unmarshal(data, &response)
+	err := b.call(ctx, "getStickerSet", map[string]interface{}{
+		"name": name,
+	}, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -797,17 +721,12 @@ func (b *API) GetStickerSet(name string) (*models.StickerSet, error) {
 // userID  - User identifier of sticker file owner
 // sticker - Png image with the sticker, must be up to 512 kilobytes in size, dimensions must not exceed 512px,
 //           and either width or height must be exactly 512px. More info on Sending Files »
-func (b *API) UploadStickerFile(userID int64, sticker models.InputFile) (*models.File, error) {
-	data, err := b.requester.JSONRequest("uploadStickerFile", map[string]interface{}{
+func (b *API) UploadStickerFile(ctx context.Context, userID int64, sticker *models.InputFile) (*models.File, error) {
+	var response models.File
+	err := b.call(ctx, "uploadStickerFile", map[string]interface{}{
 		"user_id":     userID,
 		"png_sticker": sticker,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var response models.File
-	err = json.Unmarshal(data, &response)
+	}, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -818,73 +737,170 @@ func (b *API) UploadStickerFile(userID int64, sticker models.InputFile) (*models
 // CreateNewStickerSet Use this method to create a new sticker set owned by a user. The bot will be able to edit the sticker
 // set thus created. You must use exactly one of the fields png_sticker or tgs_sticker.
 // Returns True on success.
-func (b *API) CreateNewStickerSet(request models.NewStickerSetRequest) (bool, error) {
-	_, err := b.requester.JSONRequest("createNewStickerSet", request)
-
-	return err == nil, err
+func (b *API) CreateNewStickerSet(ctx context.Context, request models.NewStickerSetRequest) error {
+	return b.call(ctx, "createNewStickerSet", request, nil)
 }
 
 // AddStickerToSet Use this method to add a new sticker to a set created by the bot. You must use exactly one of the fields
 // png_sticker or tgs_sticker. Animated stickers can be added to animated sticker sets and only to them.
 // Animated sticker sets can have up to 50 stickers. Static sticker sets can have up to 120 stickers.
 // Returns True on success.
-func (b *API) AddStickerToSet(request models.AddStickerToSetSetRequest) (bool, error) {
-	_, err := b.requester.JSONRequest("addStickerToSet", request)
-
-	return err == nil, err
+func (b *API) AddStickerToSet(ctx context.Context, request models.AddStickerToSetSetRequest) error {
+	return b.call(ctx, "addStickerToSet", request, nil)
 }
 
 // SetStickerPositionInSet Use this method to move a sticker in a set created by the bot to a specific position. Returns True on success.
 //
 // sticker  - File identifier of the sticker
 // position - New sticker position in the set, zero-based
-func (b *API) SetStickerPositionInSet(sticker string, position int) (bool, error) {
-	_, err := b.requester.JSONRequest("setStickerPositionInSet", map[string]interface{}{
+func (b *API) SetStickerPositionInSet(ctx context.Context, sticker string, position int) error {
+	return b.call(ctx, "setStickerPositionInSet", map[string]interface{}{
 		"sticker":  sticker,
 		"position": position,
-	})
-
-	return err == nil, err
+	}, nil)
 }
 
 // DeleteStickerFromSet Use this method to delete a sticker from a set created by the bot. Returns True on success.
 //
 // sticker - File identifier of the sticker
-func (b *API) DeleteStickerFromSet(sticker string) (bool, error) {
-	_, err := b.requester.JSONRequest("deleteStickerFromSet", map[string]interface{}{
+func (b *API) DeleteStickerFromSet(ctx context.Context, sticker string) error {
+	return b.call(ctx, "deleteStickerFromSet", map[string]interface{}{
 		"sticker": sticker,
-	})
-
-	return err == nil, err
+	}, nil)
 }
 
 // SetStickerSetThumb Use this method to set the thumbnail of a sticker set. Animated thumbnails can be set for animated sticker sets only.
 // Returns True on success.
-func (b *API) SetStickerSetThumb(request models.StickerSetThumbRequest) (bool, error) {
-	_, err := b.requester.JSONRequest("setStickerSetThumb", request)
+func (b *API) SetStickerSetThumb(ctx context.Context, request models.StickerSetThumbRequest) error {
+	return b.call(ctx, "setStickerSetThumb", request, nil)
+}
 
-	return err == nil, err
+// SendInvoice Use this method to send invoices. On success, the sent Message is returned.
+func (b *API) SendInvoice(ctx context.Context, request models.InvoiceMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendInvoice", request)
+}
+
+// AnswerShippingQuery If you sent an invoice requesting a shipping address and the parameter is_flexible was specified,
+// the Bot API will send an Update with a shipping_query field to the bot. Use this method to reply to shipping
+// queries. On success, True is returned.
+func (b *API) AnswerShippingQuery(ctx context.Context, request models.AnswerShippingQuery) error {
+	return b.call(ctx, "answerShippingQuery", request, nil)
+}
+
+// AnswerPreCheckoutQuery Once the user has confirmed their payment and shipping details, the Bot API sends the final
+// confirmation in the form of an Update with the field pre_checkout_query. Use this method to respond to such
+// pre-checkout queries. On success, True is returned.
+//
+// Note: The Bot API must receive an answer within 10 seconds after the pre-checkout query was sent.
+func (b *API) AnswerPreCheckoutQuery(ctx context.Context, request models.AnswerPreCheckoutQuery) error {
+	return b.call(ctx, "answerPreCheckoutQuery", request, nil)
+}
+
+// SendGame Use this method to send a game. On success, the sent Message is returned.
+func (b *API) SendGame(ctx context.Context, request models.GameMessageRequest) (*models.Message, error) {
+	return b.sendMessage(ctx, "sendGame", request)
+}
+
+// SetGameScore Use this method to set the score of the specified user in a game. On success, if the message was sent
+// by the bot, returns the edited Message, otherwise returns True. Returns an error, if the new score is not greater
+// than the user's current score in the chat and force is False.
+func (b *API) SetGameScore(ctx context.Context, request models.GameScoreRequest) (*models.EditMessageResult, error) {
+	return b.editMessage(ctx, "setGameScore", request)
+}
+
+// GetGameHighScores Use this method to get data for high score tables. Will return the score of the specified user
+// and several of their neighbors in a game. On success, returns an Array of GameHighScore objects.
+//
+// This method will currently return scores for the target user, plus two of their closest neighbors on each side.
+// Will also return the top three users if the user and his neighbors are not among them. Please note that this
+// behavior is subject to change.
+func (b *API) GetGameHighScores(ctx context.Context, request models.GameHighScoresRequest) ([]models.GameHighScore, error) {
+	var response []models.GameHighScore
+	err := b.call(ctx, "getGameHighScores", request, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// SetPassportDataErrors Informs a user that some of the Telegram Passport elements they provided contains errors.
+// The user will not be able to re-submit their Passport to you until the errors are fixed (the contents of the field
+// for which you returned the error must change). Returns True on success.
+//
+// userID - User identifier
+// errors - An array describing the errors
+func (b *API) SetPassportDataErrors(
+	ctx context.Context,
+	userID int64,
+	errors []models.PassportElementErrorInterface,
+) error {
+	return b.call(ctx, "setPassportDataErrors", map[string]interface{}{
+		"user_id": userID,
+		"errors":  errors,
+	}, nil)
 }
 
 func (b *API) Subscribe(t models.UpdateType) <-chan models.Update {
 	return b.subscribers.Subscribe(t)
 }
 
+// SubscribeWithOptions Subscribes to updates of the type with the buffer size and the overflow policy from the options.
+// Use Unsubscribe of the subscription to cancel it alone.
+func (b *API) SubscribeWithOptions(t models.UpdateType, opts events.SubscribeOptions) *events.Subscription {
+	return b.subscribers.SubscribeWithOptions(t, opts)
+}
+
+// SubscribeAll Subscribes to updates of all types in the order they are received. Use Unsubscribe of the subscription
+// to cancel it.
+func (b *API) SubscribeAll(opts events.SubscribeOptions) *events.Subscription {
+	return b.subscribers.SubscribeAll(opts)
+}
+
 func (b *API) Unsubscribe(t models.UpdateType) {
 	b.subscribers.Unsubscribe(t)
 }
 
-func (b *API) sendMessage(method string, request interface{}) (*models.Message, error) {
-	data, err := b.requester.JSONRequest(method, request)
+// DroppedUpdates Returns the number of updates dropped by subscriptions with full buffers.
+func (b *API) DroppedUpdates() uint64 {
+	return b.subscribers.Dropped()
+}
+
+// call Sends the request and decodes its result into the result. Pass nil result for the methods returning True.
+func (b *API) call(ctx context.Context, method string, request, result interface{}) error {
+	if request == nil {
+		request = struct{}{}
+	}
+
+	data, err := b.requester.Request(ctx, method, request)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if result == nil {
+		return nil
 	}
 
+	return json.Unmarshal(data, result)
+}
+
+func (b *API) sendMessage(ctx context.Context, method string, request interface{}) (*models.Message, error) {
 	var msg models.Message
-	err = json.Unmarshal(data, &msg)
+	err := b.call(ctx, method, request, &msg)
 	if err != nil {
 		return nil, err
 	}
 
 	return &msg, nil
 }
+
+// editMessage Decodes the “Message or True” result of the edit methods.
+func (b *API) editMessage(ctx context.Context, method string, request interface{}) (*models.EditMessageResult, error) {
+	var result models.EditMessageResult
+	err := b.call(ctx, method, request, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,1059 @@
+package telegram_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/request"
+	"github.com/s-larionov/telegram-api/telegramtest"
+)
+
+// methodTest Calls the API method and describes the request it must post: the Bot API method, the parameters
+// as JSON and the names of the uploaded files.
+type methodTest struct {
+	name   string
+	method string
+	result interface{}
+	call   func(ctx context.Context, api *telegram.API) error
+	params string
+	files  []string
+}
+
+// nonRequestMethods Exported API methods which don't post a single request to the Bot API.
+var nonRequestMethods = map[string]bool{
+	"DroppedUpdates":       true,
+	"FileURL":              true,
+	"StartPolling":         true,
+	"Subscribe":            true,
+	"SubscribeAll":         true,
+	"SubscribeWithOptions": true,
+	"Unsubscribe":          true,
+	"WebhookHandler":       true,
+}
+
+var keyboard = models.NewInlineKeyboardMarkupReply([][]models.InlineKeyboardButton{
+	{{Text: "OK", CallbackData: "ok"}},
+})
+
+var methodTests = []methodTest{
+	{
+		name:   "SetWebhook",
+		method: "setWebhook",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetWebhook(ctx, models.WebhookRequest{
+				URL:            "https://example.com/hook",
+				MaxConnections: 10,
+				AllowedUpdates: []models.UpdateType{models.UpdateTypeMessage},
+				SecretToken:    "secret",
+			})
+		},
+		params: `{"url":"https://example.com/hook","max_connections":10,"allowed_updates":["message"],"secret_token":"secret"}`,
+	},
+	{
+		name:   "SetWebhook",
+		method: "setWebhook",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetWebhook(ctx, models.WebhookRequest{
+				URL:         "https://example.com/hook",
+				Certificate: models.FromBytes("cert.pem", []byte("certificate")),
+			})
+		},
+		params: `{"url":"https://example.com/hook"}`,
+		files:  []string{"certificate"},
+	},
+	{
+		name:   "GetUpdates",
+		method: "getUpdates",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetUpdates(ctx, models.UpdateRequest{Offset: 5, Limit: 10})
+			return err
+		},
+		params: `{"offset":5,"limit":10}`,
+	},
+	{
+		name:   "GetWebhookInfo",
+		method: "getWebhookInfo",
+		result: models.WebhookInfo{URL: "https://example.com/hook"},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetWebhookInfo(ctx)
+			return err
+		},
+		params: `{}`,
+	},
+	{
+		name:   "DeleteWebhook",
+		method: "deleteWebhook",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.DeleteWebhook(ctx)
+		},
+		params: `{}`,
+	},
+	{
+		name:   "GetMe",
+		method: "getMe",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetMe(ctx)
+			return err
+		},
+		params: `{}`,
+	},
+	{
+		name:   "ForwardMessage",
+		method: "forwardMessage",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.ForwardMessage(ctx, models.ForwardMessageRequest{
+				ChatID:              "1",
+				FromChatID:          "2",
+				MessageID:           3,
+				DisableNotification: true,
+			})
+			return err
+		},
+		params: `{"chat_id":"1","from_chat_id":"2","message_id":3,"disable_notification":true}`,
+	},
+	{
+		name:   "SendMessage",
+		method: "sendMessage",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendMessage(ctx, models.MessageRequest{
+				MessageRequestBase: models.MessageRequestBase{
+					ChatID:           "1",
+					ParseMode:        models.ParseModeHTML,
+					ReplyToMessageID: 2,
+					ReplyMarkup:      keyboard,
+				},
+				Text: "<b>hi</b>",
+			})
+			return err
+		},
+		params: `{"chat_id":"1","parse_mode":"html","reply_to_message_id":2,` +
+			`"reply_markup":{"inline_keyboard":[[{"text":"OK","callback_data":"ok"}]]},"text":"<b>hi</b>"}`,
+	},
+	{
+		name:   "SendPhoto",
+		method: "sendPhoto",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendPhoto(ctx, models.PhotoMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Photo:              models.FromFileID("photo-id"),
+				Caption:            "caption",
+			})
+			return err
+		},
+		params: `{"chat_id":"1","photo":"photo-id","caption":"caption"}`,
+	},
+	{
+		name:   "SendPhoto",
+		method: "sendPhoto",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendPhoto(ctx, models.PhotoMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1", ReplyMarkup: keyboard},
+				Photo:              models.FromBytes("photo.jpg", []byte("photo")),
+			})
+			return err
+		},
+		params: `{"chat_id":"1","reply_markup":{"inline_keyboard":[[{"text":"OK","callback_data":"ok"}]]}}`,
+		files:  []string{"photo"},
+	},
+	{
+		name:   "SendAudio",
+		method: "sendAudio",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendAudio(ctx, models.AudioMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Audio:              models.FromBytes("audio.mp3", []byte("audio")),
+				Duration:           60,
+				Performer:          "performer",
+				Title:              "title",
+				Thumb:              models.FromBytes("thumb.jpg", []byte("thumb")),
+			})
+			return err
+		},
+		params: `{"chat_id":"1","duration":"60","performer":"performer","title":"title"}`,
+		files:  []string{"audio", "thumb"},
+	},
+	{
+		name:   "SendDocument",
+		method: "sendDocument",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendDocument(ctx, models.DocumentMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Document:           models.FromURL("https://example.com/file.pdf"),
+			})
+			return err
+		},
+		params: `{"chat_id":"1","document":"https://example.com/file.pdf"}`,
+	},
+	{
+		name:   "SendVideo",
+		method: "sendVideo",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendVideo(ctx, models.VideoMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Video:              models.FromBytes("video.mp4", []byte("video")),
+				Width:              640,
+				Height:             480,
+				SupportsStreaming:  true,
+			})
+			return err
+		},
+		params: `{"chat_id":"1","width":"640","height":"480","supports_streaming":"true"}`,
+		files:  []string{"video"},
+	},
+	{
+		name:   "SendAnimation",
+		method: "sendAnimation",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendAnimation(ctx, models.AnimationMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Animation:          models.FromFileID("animation-id"),
+				Duration:           3,
+			})
+			return err
+		},
+		params: `{"chat_id":"1","animation":"animation-id","duration":3}`,
+	},
+	{
+		name:   "SendVoice",
+		method: "sendVoice",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendVoice(ctx, models.VoiceMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Voice:              models.FromBytes("voice.ogg", []byte("voice")),
+			})
+			return err
+		},
+		params: `{"chat_id":"1"}`,
+		files:  []string{"voice"},
+	},
+	{
+		name:   "SendVideoNote",
+		method: "sendVideoNote",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendVideoNote(ctx, models.VideoNoteMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				VideoNote:          models.FromFileID("note-id"),
+				Length:             240,
+			})
+			return err
+		},
+		params: `{"chat_id":"1","video_note":"note-id","length":240}`,
+	},
+	{
+		name:   "SendMediaGroup",
+		method: "sendMediaGroup",
+		result: []models.Message{{ID: 1}, {ID: 2}},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendMediaGroup(ctx, models.MediaGroupMessageRequest{
+				ChatID: "1",
+				Media: []models.InputMediaInterface{
+					models.NewInputMediaPhoto(models.FromFileID("photo-id")),
+					models.NewInputMediaPhoto(models.FromBytes("photo.jpg", []byte("photo"))),
+				},
+			})
+			return err
+		},
+		params: `{"chat_id":"1","media":[{"type":"photo","media":"photo-id"},{"type":"photo","media":"attach://file0"}]}`,
+		files:  []string{"file0"},
+	},
+	{
+		name:   "SendLocation",
+		method: "sendLocation",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendLocation(ctx, models.LocationMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Latitude:           51.5,
+				Longitude:          -0.12,
+				LivePeriod:         60,
+			})
+			return err
+		},
+		params: `{"chat_id":"1","latitude":51.5,"longitude":-0.12,"live_period":60}`,
+	},
+	{
+		name:   "EditMessageLiveLocation",
+		method: "editMessageLiveLocation",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.EditMessageLiveLocation(ctx, models.EditMessageLiveLocation{
+				ChatID:    "1",
+				MessageID: 2,
+				Latitude:  51.5,
+				Longitude: -0.12,
+			})
+			return err
+		},
+		params: `{"chat_id":"1","message_id":2,"latitude":51.5,"longitude":-0.12}`,
+	},
+	{
+		name:   "EditMessageLiveLocation",
+		method: "editMessageLiveLocation",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.EditMessageLiveLocation(ctx, models.EditMessageLiveLocation{
+				InlineMessageID: "inline-id",
+				Latitude:        51.5,
+				Longitude:       -0.12,
+			})
+			return err
+		},
+		params: `{"inline_message_id":"inline-id","latitude":51.5,"longitude":-0.12}`,
+	},
+	{
+		name:   "StopMessageLiveLocation",
+		method: "stopMessageLiveLocation",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.StopMessageLiveLocation(ctx, models.StopMessageLiveLocation{InlineMessageID: "inline-id"})
+			return err
+		},
+		params: `{"inline_message_id":"inline-id"}`,
+	},
+	{
+		name:   "SendVenue",
+		method: "sendVenue",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendVenue(ctx, models.VenueMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Latitude:           51.5,
+				Longitude:          -0.12,
+				Title:              "title",
+				Address:            "address",
+			})
+			return err
+		},
+		params: `{"chat_id":"1","latitude":51.5,"longitude":-0.12,"title":"title","address":"address"}`,
+	},
+	{
+		name:   "SendContact",
+		method: "sendContact",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendContact(ctx, models.ContactMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				PhoneNumber:        "+10000000000",
+				FirstName:          "John",
+			})
+			return err
+		},
+		params: `{"chat_id":"1","phone_number":"+10000000000","first_name":"John"}`,
+	},
+	{
+		name:   "SendPoll",
+		method: "sendPoll",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendPoll(ctx, models.PollMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Question:           "question",
+				Options:            []string{"a", "b"},
+				IsAnonymous:        true,
+				Type:               models.PollTypeQuiz,
+				CorrectOptionID:    1,
+			})
+			return err
+		},
+		params: `{"chat_id":"1","question":"question","options":["a","b"],"is_anonymous":true,"type":"quiz",` +
+			`"correct_option_id":1}`,
+	},
+	{
+		name:   "StopPoll",
+		method: "stopPoll",
+		result: models.Poll{ID: "poll-id", IsClosed: true},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.StopPoll(ctx, models.StopPollRequest{ChatID: "1", MessageID: 2})
+			return err
+		},
+		params: `{"chat_id":"1","message_id":2}`,
+	},
+	{
+		name:   "SendDice",
+		method: "sendDice",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendDice(ctx, models.DiceMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1", DisableNotification: true},
+			})
+			return err
+		},
+		params: `{"chat_id":"1","disable_notification":true}`,
+	},
+	{
+		name:   "DeleteMessage",
+		method: "deleteMessage",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.DeleteMessage(ctx, "1", 2)
+		},
+		params: `{"chat_id":"1","message_id":2}`,
+	},
+	{
+		name:   "SendChatAction",
+		method: "sendChatAction",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SendChatAction(ctx, "1", models.ChatActionTyping)
+		},
+		params: `{"chat_id":"1","action":"typing"}`,
+	},
+	{
+		name:   "GetUserProfilePhotos",
+		method: "getUserProfilePhotos",
+		result: models.UserProfilePhotos{TotalCount: 0},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetUserProfilePhotos(ctx, models.UserProfilePhotosRequest{UserID: 1, Limit: 10})
+			return err
+		},
+		params: `{"user_id":1,"limit":10}`,
+	},
+	{
+		name:   "GetFile",
+		method: "getFile",
+		result: models.File{FileID: "file-id", FilePath: "documents/file.txt"},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetFile(ctx, "file-id")
+			return err
+		},
+		params: `{"file_id":"file-id"}`,
+	},
+	{
+		name:   "DownloadFile",
+		method: "getFile",
+		result: models.File{FileID: "file-id", FilePath: "documents/file.txt", FileSize: 7},
+		call: func(ctx context.Context, api *telegram.API) error {
+			var b bytes.Buffer
+			if err := api.DownloadFile(ctx, "file-id", &b); err != nil {
+				return err
+			}
+
+			if b.String() != "content" {
+				return fmt.Errorf("unexpected content: %q", b.String())
+			}
+
+			return nil
+		},
+		params: `{"file_id":"file-id"}`,
+	},
+	{
+		name:   "KickChatMember",
+		method: "kickChatMember",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.KickChatMember(ctx, "-100", 2, 1600000000)
+		},
+		params: `{"chat_id":"-100","user_id":2,"until_date":1600000000}`,
+	},
+	{
+		name:   "UnbanChatMember",
+		method: "unbanChatMember",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.UnbanChatMember(ctx, "-100", 2)
+		},
+		params: `{"chat_id":"-100","user_id":2}`,
+	},
+	{
+		name:   "RestrictChatMember",
+		method: "restrictChatMember",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.RestrictChatMember(ctx, models.ChatMemberRestrictionsRequest{
+				ChatID:         "-100",
+				UserID:         2,
+				Permissions:    models.ChatPermissions{CanSendMessages: true, CanSendPolls: true},
+				UntilTimestamp: 1600000000,
+			})
+		},
+		params: `{"chat_id":"-100","user_id":2,"permissions":{"can_send_messages":true,"can_send_polls":true},` +
+			`"until_date":1600000000}`,
+	},
+	{
+		name:   "PromoteChatMember",
+		method: "promoteChatMember",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.PromoteChatMember(ctx, models.ChatMemberPromotionRequest{
+				ChatID:         "-100",
+				UserID:         2,
+				CanPinMessages: true,
+			})
+		},
+		params: `{"chat_id":"-100","user_id":2,"can_pin_messages":true}`,
+	},
+	{
+		name:   "SetChatAdministratorCustomTitle",
+		method: "setChatAdministratorCustomTitle",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetChatAdministratorCustomTitle(ctx, "-100", 2, "title")
+		},
+		params: `{"chat_id":"-100","user_id":2,"custom_title":"title"}`,
+	},
+	{
+		name:   "SetChatPermissions",
+		method: "setChatPermissions",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetChatPermissions(ctx, "-100", models.ChatPermissions{CanSendMessages: true})
+		},
+		params: `{"chat_id":"-100","permissions":{"can_send_messages":true}}`,
+	},
+	{
+		name:   "ExportChatInviteLink",
+		method: "exportChatInviteLink",
+		result: "https://t.me/joinchat/link",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.ExportChatInviteLink(ctx, "-100")
+			return err
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "SetChatPhoto",
+		method: "setChatPhoto",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetChatPhoto(ctx, models.ChatSetPhotoRequest{
+				ChatID: "-100",
+				Photo:  models.FromBytes("photo.jpg", []byte("photo")),
+			})
+		},
+		params: `{"chat_id":"-100"}`,
+		files:  []string{"photo"},
+	},
+	{
+		name:   "DeleteChatPhoto",
+		method: "deleteChatPhoto",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.DeleteChatPhoto(ctx, "-100")
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "SetChatTitle",
+		method: "setChatTitle",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetChatTitle(ctx, "-100", "title")
+		},
+		params: `{"chat_id":"-100","title":"title"}`,
+	},
+	{
+		name:   "SetChatDescription",
+		method: "setChatDescription",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetChatDescription(ctx, "-100", "description")
+		},
+		params: `{"chat_id":"-100","description":"description"}`,
+	},
+	{
+		name:   "PinChatMessage",
+		method: "pinChatMessage",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.PinChatMessage(ctx, "-100", 2, true)
+		},
+		params: `{"chat_id":"-100","message_id":2,"disable_notification":true}`,
+	},
+	{
+		name:   "UnpinChatMessage",
+		method: "unpinChatMessage",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.UnpinChatMessage(ctx, "-100")
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "LeaveChat",
+		method: "leaveChat",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.LeaveChat(ctx, "-100")
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "GetChat",
+		method: "getChat",
+		result: models.Chat{ID: -100, Type: models.ChatTypeSuperGroup},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetChat(ctx, "-100")
+			return err
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "GetChatAdministrators",
+		method: "getChatAdministrators",
+		result: []models.ChatMember{{User: &models.User{ID: 2}, Status: "administrator"}},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetChatAdministrators(ctx, "-100")
+			return err
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "GetChatMembersCount",
+		method: "getChatMembersCount",
+		result: 42,
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetChatMembersCount(ctx, "-100")
+			return err
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "GetChatMember",
+		method: "getChatMember",
+		result: models.ChatMember{User: &models.User{ID: 2}, Status: "member"},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetChatMember(ctx, "-100", 2)
+			return err
+		},
+		params: `{"chat_id":"-100","user_id":2}`,
+	},
+	{
+		name:   "SetChatStickerSet",
+		method: "setChatStickerSet",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetChatStickerSet(ctx, "-100", "stickers")
+		},
+		params: `{"chat_id":"-100","sticker_set_name":"stickers"}`,
+	},
+	{
+		name:   "DeleteChatStickerSet",
+		method: "deleteChatStickerSet",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.DeleteChatStickerSet(ctx, "-100")
+		},
+		params: `{"chat_id":"-100"}`,
+	},
+	{
+		name:   "AnswerCallbackQuery",
+		method: "answerCallbackQuery",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.AnswerCallbackQuery(ctx, models.AnswerCallbackQuery{
+				CallbackQueryID: "query-id",
+				Text:            "done",
+				ShowAlert:       true,
+			})
+		},
+		params: `{"callback_query_id":"query-id","text":"done","show_alert":true}`,
+	},
+	{
+		name:   "AnswerInlineQuery",
+		method: "answerInlineQuery",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.AnswerInlineQuery(ctx, models.AnswerInlineQuery{
+				InlineQueryID: "query-id",
+				Results: []models.InlineQueryResultInterface{
+					models.InlineQueryResultArticle{
+						InlineQueryResult:   models.InlineQueryResult{Type: models.InlineQueryResultTypeArticle, ID: "1"},
+						Title:               "title",
+						InputMessageContent: models.InputTextMessageContent{MessageText: "text"},
+					},
+				},
+				CacheTime: 60,
+			})
+		},
+		params: `{"inline_query_id":"query-id","results":[{"type":"article","id":"1","title":"title",` +
+			`"input_message_content":{"message_text":"text"}}],"cache_time":60}`,
+	},
+	{
+		name:   "SetMyCommands",
+		method: "setMyCommands",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetMyCommands(ctx, []models.BotCommand{{Command: "start", Description: "Start"}})
+		},
+		params: `{"commands":[{"command":"start","description":"Start"}]}`,
+	},
+	{
+		name:   "GetMyCommands",
+		method: "getMyCommands",
+		result: []models.BotCommand{{Command: "start", Description: "Start"}},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetMyCommands(ctx)
+			return err
+		},
+		params: `{}`,
+	},
+	{
+		name:   "EditMessageText",
+		method: "editMessageText",
+		result: models.Message{ID: 2},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.EditMessageText(ctx, models.EditMessageTextRequest{
+				EditMessageRequest: models.EditMessageRequest{ChatID: "1", MessageID: 2},
+				Text:               "text",
+			})
+			return err
+		},
+		params: `{"chat_id":"1","message_id":2,"text":"text"}`,
+	},
+	{
+		name:   "EditMessageText",
+		method: "editMessageText",
+		call: func(ctx context.Context, api *telegram.API) error {
+			result, err := api.EditMessageText(ctx, models.EditMessageTextRequest{
+				EditMessageRequest: models.EditMessageRequest{InlineMessageID: "inline-id"},
+				Text:               "text",
+			})
+			if err == nil && !result.InlineMessageEdited {
+				return errInlineMessageNotEdited
+			}
+			return err
+		},
+		params: `{"inline_message_id":"inline-id","text":"text"}`,
+	},
+	{
+		name:   "EditMessageCaption",
+		method: "editMessageCaption",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.EditMessageCaption(ctx, models.EditMessageCaptionRequest{
+				EditMessageRequest: models.EditMessageRequest{ChatID: "1", MessageID: 2, ParseMode: models.ParseModeHTML},
+				Caption:            "caption",
+			})
+			return err
+		},
+		params: `{"chat_id":"1","message_id":2,"parse_mode":"html","caption":"caption"}`,
+	},
+	{
+		name:   "EditMessageMedia",
+		method: "editMessageMedia",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.EditMessageMedia(ctx, models.EditMessageMediaRequest{
+				EditMessageRequest: models.EditMessageRequest{ChatID: "1", MessageID: 2},
+				Media:              models.NewInputMediaPhoto(models.FromBytes("photo.jpg", []byte("photo"))),
+			})
+			return err
+		},
+		params: `{"chat_id":"1","message_id":"2","media":{"type":"photo","media":"attach://file0"}}`,
+		files:  []string{"file0"},
+	},
+	{
+		name:   "EditMessageReplyMarkup",
+		method: "editMessageReplyMarkup",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.EditMessageReplyMarkup(ctx, models.EditMessageReplyMarkupRequest{
+				EditMessageRequest: models.EditMessageRequest{ChatID: "1", MessageID: 2, ReplyMarkup: keyboard},
+			})
+			return err
+		},
+		params: `{"chat_id":"1","message_id":2,"reply_markup":{"inline_keyboard":[[{"text":"OK","callback_data":"ok"}]]}}`,
+	},
+	{
+		name:   "SendSticker",
+		method: "sendSticker",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendSticker(ctx, models.SendStickerRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Sticker:            models.FromFileID("sticker-id"),
+			})
+			return err
+		},
+		params: `{"chat_id":"1","sticker":"sticker-id"}`,
+	},
+	{
+		name:   "GetStickerSet",
+		method: "getStickerSet",
+		result: models.StickerSet{Name: "stickers", Title: "Stickers"},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetStickerSet(ctx, "stickers")
+			return err
+		},
+		params: `{"name":"stickers"}`,
+	},
+	{
+		name:   "UploadStickerFile",
+		method: "uploadStickerFile",
+		result: models.File{FileID: "file-id"},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.UploadStickerFile(ctx, 1, models.FromBytes("sticker.png", []byte("sticker")))
+			return err
+		},
+		params: `{"user_id":"1"}`,
+		files:  []string{"png_sticker"},
+	},
+	{
+		name:   "CreateNewStickerSet",
+		method: "createNewStickerSet",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.CreateNewStickerSet(ctx, models.NewStickerSetRequest{
+				UserID:     1,
+				Name:       "stickers_by_test_bot",
+				Title:      "Stickers",
+				PngSticker: models.FromFileID("sticker-id"),
+				Emojis:     "🙂",
+			})
+		},
+		params: `{"user_id":1,"name":"stickers_by_test_bot","title":"Stickers","png_sticker":"sticker-id","emojis":"🙂"}`,
+	},
+	{
+		name:   "AddStickerToSet",
+		method: "addStickerToSet",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.AddStickerToSet(ctx, models.AddStickerToSetSetRequest{
+				UserID:     1,
+				Name:       "stickers_by_test_bot",
+				TgsSticker: models.FromBytes("sticker.tgs", []byte("sticker")),
+				Emojis:     "🙂",
+			})
+		},
+		params: `{"user_id":"1","name":"stickers_by_test_bot","emojis":"🙂"}`,
+		files:  []string{"tgs_sticker"},
+	},
+	{
+		name:   "SetStickerPositionInSet",
+		method: "setStickerPositionInSet",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetStickerPositionInSet(ctx, "sticker-id", 1)
+		},
+		params: `{"sticker":"sticker-id","position":1}`,
+	},
+	{
+		name:   "DeleteStickerFromSet",
+		method: "deleteStickerFromSet",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.DeleteStickerFromSet(ctx, "sticker-id")
+		},
+		params: `{"sticker":"sticker-id"}`,
+	},
+	{
+		name:   "SetStickerSetThumb",
+		method: "setStickerSetThumb",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetStickerSetThumb(ctx, models.StickerSetThumbRequest{
+				Name:   "stickers_by_test_bot",
+				UserID: 1,
+				Thumb:  models.FromFileID("thumb-id"),
+			})
+		},
+		params: `{"name":"stickers_by_test_bot","user_id":1,"thumb":"thumb-id"}`,
+	},
+	{
+		name:   "SendInvoice",
+		method: "sendInvoice",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendInvoice(ctx, models.InvoiceMessageRequest{
+				ChatID:         1,
+				Title:          "title",
+				Description:    "description",
+				Payload:        "payload",
+				ProviderToken:  "token",
+				StartParameter: "start",
+				Currency:       "USD",
+				Prices:         []models.LabeledPrice{{Label: "item", Amount: 145}},
+				NeedEmail:      true,
+			})
+			return err
+		},
+		params: `{"chat_id":1,"title":"title","description":"description","payload":"payload",` +
+			`"provider_token":"token","start_parameter":"start","currency":"USD",` +
+			`"prices":[{"label":"item","amount":145}],"need_email":true}`,
+	},
+	{
+		name:   "AnswerShippingQuery",
+		method: "answerShippingQuery",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.AnswerShippingQuery(ctx, models.AnswerShippingQuery{
+				ShippingQueryID: "query-id",
+				Ok:              false,
+				ErrorMessage:    "unavailable",
+			})
+		},
+		params: `{"shipping_query_id":"query-id","ok":false,"error_message":"unavailable"}`,
+	},
+	{
+		name:   "AnswerPreCheckoutQuery",
+		method: "answerPreCheckoutQuery",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.AnswerPreCheckoutQuery(ctx, models.AnswerPreCheckoutQuery{
+				PreCheckoutQueryID: "query-id",
+				Ok:                 true,
+			})
+		},
+		params: `{"pre_checkout_query_id":"query-id","ok":true}`,
+	},
+	{
+		name:   "SendGame",
+		method: "sendGame",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SendGame(ctx, models.GameMessageRequest{ChatID: 1, GameShortName: "game"})
+			return err
+		},
+		params: `{"chat_id":1,"game_short_name":"game"}`,
+	},
+	{
+		name:   "SetGameScore",
+		method: "setGameScore",
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.SetGameScore(ctx, models.GameScoreRequest{
+				UserID:          1,
+				Score:           100,
+				Force:           true,
+				InlineMessageID: "inline-id",
+			})
+			return err
+		},
+		params: `{"user_id":1,"score":100,"force":true,"inline_message_id":"inline-id"}`,
+	},
+	{
+		name:   "GetGameHighScores",
+		method: "getGameHighScores",
+		result: []models.GameHighScore{{Position: 1, User: &models.User{ID: 1}, Score: 100}},
+		call: func(ctx context.Context, api *telegram.API) error {
+			_, err := api.GetGameHighScores(ctx, models.GameHighScoresRequest{UserID: 1, ChatID: 1, MessageID: 2})
+			return err
+		},
+		params: `{"user_id":1,"chat_id":1,"message_id":2}`,
+	},
+	{
+		name:   "SetPassportDataErrors",
+		method: "setPassportDataErrors",
+		call: func(ctx context.Context, api *telegram.API) error {
+			return api.SetPassportDataErrors(ctx, 1, []models.PassportElementErrorInterface{
+				models.PassportElementErrorDataField{
+					PassportElementError: models.PassportElementError{
+						Source:  models.PassportElementErrorSourceData,
+						Type:    models.PassportElementTypePersonalDetails,
+						Message: "invalid name",
+					},
+					FieldName: "first_name",
+					DataHash:  "hash",
+				},
+			})
+		},
+		params: `{"user_id":1,"errors":[{"source":"data","type":"personal_details","message":"invalid name",` +
+			`"field_name":"first_name","data_hash":"hash"}]}`,
+	},
+}
+
+var errInlineMessageNotEdited = errors.New("edit of the inline message wasn't recognized")
+
+func TestAPIMethods(t *testing.T) {
+	server := telegramtest.NewServer()
+	defer server.Close()
+
+	server.AddFile("documents/file.txt", []byte("content"))
+
+	api := server.API()
+
+	for _, tt := range methodTests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			server.Reset()
+			if tt.result != nil {
+				server.Respond(tt.method, tt.result)
+			}
+
+			err := tt.call(context.Background(), api)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			calls := server.Calls()
+			if len(calls) != 1 {
+				t.Fatalf("unexpected number of calls: got %d, want 1", len(calls))
+			}
+
+			call := calls[0]
+			if call.Method != tt.method {
+				t.Errorf("unexpected method: got %s, want %s", call.Method, tt.method)
+			}
+
+			var params map[string]interface{}
+			err = json.Unmarshal([]byte(tt.params), &params)
+			if err != nil {
+				t.Fatalf("invalid expected params: %v", err)
+			}
+
+			if !reflect.DeepEqual(call.Params, params) {
+				got, _ := json.Marshal(call.Params)
+				t.Errorf("unexpected params:\n got: %s\nwant: %s", got, tt.params)
+			}
+
+			files := make([]string, 0, len(call.Files))
+			for name := range call.Files {
+				files = append(files, name)
+			}
+			sort.Strings(files)
+
+			want := append([]string{}, tt.files...)
+			sort.Strings(want)
+
+			if !reflect.DeepEqual(files, want) {
+				t.Errorf("unexpected files: got %q, want %q", files, want)
+			}
+		})
+	}
+}
+
+func TestAPIMethodsCoverage(t *testing.T) {
+	tested := make(map[string]bool)
+	for _, tt := range methodTests {
+		tested[tt.name] = true
+	}
+
+	apiType := reflect.TypeOf(&telegram.API{})
+	for i := 0; i < apiType.NumMethod(); i++ {
+		name := apiType.Method(i).Name
+		if !tested[name] && !nonRequestMethods[name] {
+			t.Errorf("method %s isn't covered by methodTests", name)
+		}
+	}
+}
+
+// countingWriter Counts written bytes without keeping them.
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+
+	return len(p), nil
+}
+
+func TestDownloadFileSizeLimit(t *testing.T) {
+	cases := []struct {
+		name     string
+		fileSize int
+		content  int
+		written  int64
+		err      error
+	}{
+		{
+			name:     "limit",
+			fileSize: request.MaxDownloadFileSize,
+			content:  request.MaxDownloadFileSize,
+			written:  request.MaxDownloadFileSize,
+		},
+		{
+			// the reported size is checked before downloading
+			name:     "file size",
+			fileSize: request.MaxDownloadFileSize + 1,
+			content:  request.MaxDownloadFileSize + 1,
+			err:      request.ErrFileTooLarge,
+		},
+		{
+			// without the reported size the streamed body is cut right after the limit
+			name:    "body",
+			content: request.MaxDownloadFileSize + 1<<20,
+			written: request.MaxDownloadFileSize + 1,
+			err:     request.ErrFileTooLarge,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := telegramtest.NewServer()
+			defer server.Close()
+
+			server.AddFile("documents/large.bin", make([]byte, tc.content))
+			server.Respond("getFile", models.File{
+				FileID:   "file-id",
+				FilePath: "documents/large.bin",
+				FileSize: tc.fileSize,
+			})
+
+			var w countingWriter
+			err := server.API().DownloadFile(context.Background(), "file-id", &w)
+			if err != tc.err {
+				t.Errorf("unexpected error: got %v, want %v", err, tc.err)
+			}
+
+			if int64(w) != tc.written {
+				t.Errorf("unexpected number of written bytes: got %d, want %d", w, tc.written)
+			}
+		})
+	}
+}
@@ -2,68 +2,240 @@ package base
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/events"
 	"github.com/s-larionov/telegram-api/models"
 )
 
-type handler func(update models.Update) error
+const (
+	defaultWorkers         = 8
+	defaultQueueSize       = 64
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+var ErrShutdownTimeout = errors.New("updates weren't processed before the shutdown timeout")
+
+// BotOptions Settings of the Bot.
+type BotOptions struct {
+	// Number of workers processing updates in parallel. Updates of one session are always processed by the same
+	// worker. Defaults to 8.
+	Workers int
+
+	// Size of the queue of each worker. Defaults to 64.
+	QueueSize int
+
+	// Maximum time of processing one update. The timeout is advisory: it cancels the context passed to the steps
+	// implementing ContextStep, while Step.Process, Step.OnLeave and the storage don't receive the context and aren't
+	// interrupted, so a slow step still holds its worker and delays the other sessions of the same worker. The session
+	// of an update exceeding the timeout isn't stored, such updates are logged and counted by TimedOut.
+	// Not limited if it is zero.
+	HandlerTimeout time.Duration
+
+	// Time to finish processing of received updates after the context of Run is canceled. Defaults to 10 seconds.
+	ShutdownTimeout time.Duration
+}
+
+func (o BotOptions) withDefaults() BotOptions {
+	if o.Workers <= 0 {
+		o.Workers = defaultWorkers
+	}
+
+	if o.QueueSize <= 0 {
+		o.QueueSize = defaultQueueSize
+	}
+
+	if o.ShutdownTimeout <= 0 {
+		o.ShutdownTimeout = defaultShutdownTimeout
+	}
+
+	return o
+}
+
+// RunError Errors occurred while the bot was stopping. It matches each of them by errors.Is.
+type RunError []error
+
+func (e RunError) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
 
+	return strings.Join(messages, "; ")
+}
+
+func (e RunError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Bot Passes updates received by the API to the flow. Updates of one session are processed one at a time
+// in the order they are received, updates of different sessions are processed in parallel by the workers.
 type Bot struct {
-	Flow          *Flow
-	API           *telegram.API
-	subscriptions []<-chan models.Update
-	wg            sync.WaitGroup
+	Flow *Flow
+	API  *telegram.API
+	opts BotOptions
+
+	// accessed atomically
+	skipped  int64
+	timedOut int64
 }
 
 func NewBot(api *telegram.API, flow *Flow) *Bot {
+	return NewBotWithOptions(api, flow, BotOptions{})
+}
+
+func NewBotWithOptions(api *telegram.API, flow *Flow, opts BotOptions) *Bot {
 	return &Bot{
 		API:  api,
 		Flow: flow,
+		opts: opts.withDefaults(),
 	}
 }
 
+// Run Processes updates until the context is canceled. Then the bot cancels its subscription, processes the updates
+// already received and waits for the running handlers up to the shutdown timeout. Returns ctx.Err() after a graceful
+// shutdown, or RunError with ErrShutdownTimeout if not all updates were processed in time. In the latter case
+// the remaining updates are skipped, but the handlers still running aren't interrupted and may outlive Run.
+//
+// Updates arriving after the shutdown started aren't accepted, so they aren't confirmed to Telegram and are delivered
+// again. It is safe to stop the transports (webhook.Server, API.StartPolling) with the same context. Only
+// the subscription of the bot is cancelled, so the API keeps working and Run can be called again.
 func (b *Bot) Run(ctx context.Context) error {
-	b.subscribe(ctx, models.UpdateTypeMessage, b.Flow.OnMessage)
-	b.subscribe(ctx, models.UpdateTypeEditedMessage, b.Flow.OnMessageEdit)
-	b.subscribe(ctx, models.UpdateTypeChannelPost, b.Flow.OnChannelPost)
-	b.subscribe(ctx, models.UpdateTypeEditedChannelPost, b.Flow.OnChannelPostEdit)
-	b.subscribe(ctx, models.UpdateTypeInlineQuery, b.Flow.OnInlineQuery)
-	b.subscribe(ctx, models.UpdateTypeChosenInlineResult, b.Flow.OnChosenInlineResult)
-	b.subscribe(ctx, models.UpdateTypeCallbackQuery, b.Flow.OnCallbackQuery)
-	b.subscribe(ctx, models.UpdateTypeShippingQuery, b.Flow.OnShippingQuery)
-	b.subscribe(ctx, models.UpdateTypePreCheckoutQuery, b.Flow.OnPreCheckoutQuery)
-	b.subscribe(ctx, models.UpdateTypePoll, b.Flow.OnPoll)
-	b.subscribe(ctx, models.UpdateTypePollAnswer, b.Flow.OnPollAnswer)
-
-	b.wg.Wait()
-
-	return nil
-}
+	// handlers outlive ctx during the shutdown, so they get their own context
+	handlersCtx, cancelHandlers := context.WithCancel(context.Background())
+	defer cancelHandlers()
+
+	atomic.StoreInt64(&b.skipped, 0)
+
+	queues := make([]chan models.Update, b.opts.Workers)
+	var workers sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan models.Update, b.opts.QueueSize)
+
+		workers.Add(1)
+		go func(queue <-chan models.Update) {
+			defer workers.Done()
+
+			b.work(handlersCtx, queue)
+		}(queues[i])
+	}
+
+	// one subscription to all types keeps the order of updates, e.g. of a message and the following callback query
+	subscription := b.API.SubscribeAll(events.SubscribeOptions{})
 
-func (b *Bot) subscribe(ctx context.Context, t models.UpdateType, handler handler) {
-	ch := b.API.Subscribe(t)
-	b.wg.Add(1)
+	routed := make(chan struct{})
 	go func() {
-		defer b.wg.Done()
-
-		var u models.Update
-		for {
-			select {
-			case <-ctx.Done():
-				// TODO: ctx.Err()
-				return
-			case u = <-ch:
-				err := handler(u)
-				if err == ErrUnsupportedEvent {
-					log.WithError(err).Info("unsupported event")
-				} else if err != nil {
-					log.WithError(err).Error("unable to process request")
-				}
-			}
+		defer close(routed)
+
+		b.route(subscription.Updates(), queues)
+	}()
+
+	<-ctx.Done()
+
+	drained := make(chan struct{})
+	go func() {
+		// updates accepted by the subscription before it is cancelled are still read from its channel, the rest
+		// aren't accepted and are delivered by Telegram again
+		subscription.Unsubscribe()
+
+		<-routed
+		for _, queue := range queues {
+			close(queue)
 		}
+
+		workers.Wait()
+		close(drained)
 	}()
+
+	timer := time.NewTimer(b.opts.ShutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	cancelHandlers()
+
+	return RunError{
+		ctx.Err(),
+		fmt.Errorf("%w: %d skipped", ErrShutdownTimeout, b.pending(queues)),
+	}
+}
+
+// route Sends updates to the queues by their session keys, so that updates of one session always go to the same
+// worker.
+func (b *Bot) route(updates <-chan models.Update, queues []chan models.Update) {
+	for u := range updates {
+		queues[b.shard(u, len(queues))] <- u
+	}
+}
+
+func (b *Bot) shard(u models.Update, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(b.Flow.SessionKey(u).String()))
+
+	return int(h.Sum32() % uint32(n))
+}
+
+func (b *Bot) work(ctx context.Context, queue <-chan models.Update) {
+	for u := range queue {
+		if ctx.Err() != nil {
+			// the shutdown timeout has expired
+			atomic.AddInt64(&b.skipped, 1)
+			continue
+		}
+
+		err := b.handle(ctx, u)
+		if err == ErrUnsupportedEvent {
+			log.WithError(err).Info("unsupported event")
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			atomic.AddInt64(&b.timedOut, 1)
+			log.WithField("update_id", u.ID).WithField("timeout", b.opts.HandlerTimeout).
+				Warn("update processing exceeded the handler timeout")
+		} else if err != nil {
+			log.WithError(err).WithField("update_id", u.ID).Error("unable to process request")
+		}
+	}
+}
+
+func (b *Bot) handle(ctx context.Context, u models.Update) error {
+	if b.opts.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.opts.HandlerTimeout)
+		defer cancel()
+	}
+
+	return b.Flow.OnUpdateContext(ctx, u)
+}
+
+// TimedOut Returns the number of updates which exceeded the handler timeout.
+func (b *Bot) TimedOut() int64 {
+	return atomic.LoadInt64(&b.timedOut)
+}
+
+// pending Returns the number of updates which won't be processed, including the ones still waiting in the queues.
+func (b *Bot) pending(queues []chan models.Update) int64 {
+	n := atomic.LoadInt64(&b.skipped)
+	for _, queue := range queues {
+		n += int64(len(queue))
+	}
+
+	return n
 }
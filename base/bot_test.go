@@ -0,0 +1,357 @@
+package base_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/base"
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/telegramtest"
+)
+
+// recordingStep Records identifiers of processed updates in the order of processing. Messages with the “slow” text
+// are processed until the release channel is closed.
+type recordingStep struct {
+	base.StepBase
+
+	release chan struct{}
+
+	lock      sync.Mutex
+	processed map[int64]bool
+	order     []int64
+
+	// number of updates of each session being processed and its maximum
+	active   map[base.SessionKey]int
+	parallel int
+}
+
+func newRecordingStep(api *telegram.API) *recordingStep {
+	return &recordingStep{
+		StepBase:  base.NewStepBase("record", api),
+		release:   make(chan struct{}),
+		processed: make(map[int64]bool),
+		active:    make(map[base.SessionKey]int),
+	}
+}
+
+func (s *recordingStep) Process(session base.Session, u models.Update) base.StepResult {
+	s.lock.Lock()
+	s.active[session.GetKey()]++
+	if s.active[session.GetKey()] > s.parallel {
+		s.parallel = s.active[session.GetKey()]
+	}
+	s.lock.Unlock()
+
+	if u.Message != nil && u.Message.Text == "slow" {
+		<-s.release
+	}
+
+	// gives other workers a chance to process updates of the same session concurrently
+	time.Sleep(10 * time.Microsecond)
+
+	s.lock.Lock()
+	s.active[session.GetKey()]--
+	s.processed[u.ID] = true
+	s.order = append(s.order, u.ID)
+	s.lock.Unlock()
+
+	return base.NewStepResult(nil)
+}
+
+func (s *recordingStep) count() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.processed)
+}
+
+// recordingStorage Counts stored sessions by their keys.
+type recordingStorage struct {
+	base.Storage
+
+	lock   sync.Mutex
+	stored map[base.SessionKey]int
+}
+
+func (s *recordingStorage) Store(session base.Session) error {
+	s.lock.Lock()
+	s.stored[session.GetKey()]++
+	s.lock.Unlock()
+
+	return s.Storage.Store(session)
+}
+
+func (s *recordingStorage) count(key base.SessionKey) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.stored[key]
+}
+
+type botTest struct {
+	server  *telegramtest.Server
+	handler http.Handler
+	step    *recordingStep
+	storage *recordingStorage
+	flow    *base.Flow
+	bot     *base.Bot
+	cancel  context.CancelFunc
+	done    chan error
+}
+
+// startBot Runs the bot and waits until it processes the first update.
+func startBot(t *testing.T, opts base.BotOptions) *botTest {
+	t.Helper()
+
+	server := telegramtest.NewServer()
+	t.Cleanup(server.Close)
+
+	api := server.API()
+	step := newRecordingStep(api)
+	storage := &recordingStorage{Storage: base.NewInMemoryStorage(), stored: make(map[base.SessionKey]int)}
+
+	flow, err := base.NewFlowWithSteps(storage, []base.Step{step})
+	if err != nil {
+		t.Fatalf("unable to create the flow: %v", err)
+	}
+
+	bt := &botTest{
+		server:  server,
+		handler: http.HandlerFunc(api.WebhookHandler),
+		step:    step,
+		storage: storage,
+		flow:    flow,
+		bot:     base.NewBotWithOptions(api, flow, opts),
+	}
+
+	// nobody has subscribed yet, so the update isn't confirmed and Telegram delivers it again
+	if code := bt.post(t, 1, "ping"); code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status of the webhook before the start: got %d, want %d",
+			code, http.StatusServiceUnavailable)
+	}
+
+	bt.run(t)
+
+	return bt
+}
+
+// run Runs the bot and waits until it processes a ping.
+func (bt *botTest) run(t *testing.T) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	bt.cancel = cancel
+	bt.done = make(chan error, 1)
+
+	processed := bt.step.count()
+
+	go func() {
+		bt.done <- bt.bot.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for bt.post(t, 1, "ping") != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("the bot doesn't accept updates")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	for bt.step.count() == processed {
+		if time.Now().After(deadline) {
+			t.Fatal("the bot doesn't process updates")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (bt *botTest) post(t *testing.T, userID int64, text string) int {
+	t.Helper()
+
+	return bt.postUpdate(t, models.Update{
+		Message: &models.Message{
+			From: &models.User{ID: userID},
+			Chat: &models.Chat{ID: userID, Type: models.ChatTypePrivate},
+			Text: text,
+		},
+	})
+}
+
+func (bt *botTest) postUpdate(t *testing.T, u models.Update) int {
+	t.Helper()
+
+	w, err := bt.server.PostUpdate(bt.handler, u)
+	if err != nil {
+		t.Fatalf("unable to post the update: %v", err)
+	}
+
+	return w.Code
+}
+
+func (bt *botTest) stop(t *testing.T) error {
+	t.Helper()
+
+	bt.cancel()
+
+	select {
+	case err := <-bt.done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("the bot didn't stop")
+	}
+
+	return nil
+}
+
+func TestBotRunProcessesReceivedUpdates(t *testing.T) {
+	bt := startBot(t, base.BotOptions{Workers: 2})
+
+	before := bt.step.count()
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		if code := bt.post(t, int64(i%5+1), "hello"); code != http.StatusOK {
+			t.Fatalf("unexpected status of the webhook: got %d, want %d", code, http.StatusOK)
+		}
+	}
+
+	err := bt.stop(t)
+	if err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	if got := bt.step.count() - before; got != n {
+		t.Errorf("unexpected number of processed updates: got %d, want %d", got, n)
+	}
+
+	if code := bt.post(t, 1, "late"); code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status of the webhook after the shutdown: got %d, want %d",
+			code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestBotRunKeepsSessionOrder(t *testing.T) {
+	bt := startBot(t, base.BotOptions{Workers: 4})
+
+	user := &models.User{ID: 7}
+	chat := &models.Chat{ID: 7, Type: models.ChatTypePrivate}
+
+	var want []int64
+	for id := int64(1000); id < 1400; id++ {
+		u := models.Update{ID: id}
+		if id%2 == 0 {
+			u.Message = &models.Message{ID: id, From: user, Chat: chat, Text: "hello"}
+		} else {
+			u.CallbackQuery = &models.CallbackQuery{
+				ID:      "query",
+				From:    user,
+				Message: &models.Message{ID: id - 1, Chat: chat},
+				Data:    "button",
+			}
+		}
+
+		if code := bt.postUpdate(t, u); code != http.StatusOK {
+			t.Fatalf("unexpected status of the webhook: got %d, want %d", code, http.StatusOK)
+		}
+
+		want = append(want, id)
+	}
+
+	if err := bt.stop(t); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	bt.step.lock.Lock()
+	defer bt.step.lock.Unlock()
+
+	// skips the pings of startBot
+	var got []int64
+	for _, id := range bt.step.order {
+		if id >= want[0] {
+			got = append(got, id)
+		}
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("updates of the session are processed out of order: %v", got)
+	}
+
+	if bt.step.parallel != 1 {
+		t.Errorf("updates of the session are processed concurrently by %d workers", bt.step.parallel)
+	}
+}
+
+func TestBotRunShutdownTimeout(t *testing.T) {
+	bt := startBot(t, base.BotOptions{Workers: 1, ShutdownTimeout: 50 * time.Millisecond})
+	defer close(bt.step.release)
+
+	for i := 0; i < 3; i++ {
+		bt.post(t, 1, "slow")
+	}
+
+	err := bt.stop(t)
+
+	if !errors.Is(err, base.ErrShutdownTimeout) {
+		t.Errorf("unexpected error: got %v, want %v", err, base.ErrShutdownTimeout)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestBotRunRestarts(t *testing.T) {
+	bt := startBot(t, base.BotOptions{})
+
+	if err := bt.stop(t); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	bt.run(t)
+
+	if err := bt.stop(t); err != context.Canceled {
+		t.Errorf("unexpected error after the restart: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestBotHandlerTimeout(t *testing.T) {
+	bt := startBot(t, base.BotOptions{HandlerTimeout: 20 * time.Millisecond})
+
+	slow := models.Update{
+		ID: 100,
+		Message: &models.Message{
+			From: &models.User{ID: 2},
+			Chat: &models.Chat{ID: 2, Type: models.ChatTypePrivate},
+			Text: "slow",
+		},
+	}
+
+	if code := bt.postUpdate(t, slow); code != http.StatusOK {
+		t.Fatalf("unexpected status of the webhook: got %d, want %d", code, http.StatusOK)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(bt.step.release)
+
+	if err := bt.stop(t); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	if n := bt.bot.TimedOut(); n != 1 {
+		t.Errorf("unexpected number of timed out updates: got %d, want 1", n)
+	}
+
+	if n := bt.storage.count(bt.flow.SessionKey(slow)); n != 0 {
+		t.Errorf("the session of the timed out update is stored %d times", n)
+	}
+}
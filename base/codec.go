@@ -0,0 +1,60 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SessionEncodingVersion Version of the session encoding written by MarshalSession
+const SessionEncodingVersion = 1
+
+var ErrUnsupportedSessionVersion = errors.New("unsupported session encoding version")
+
+// encodedSession The versioned envelope of the session stored by persistent storages
+type encodedSession struct {
+	Version int             `json:"v"`
+	UserID  int64           `json:"user_id,omitempty"`
+	ChatID  int64           `json:"chat_id,omitempty"`
+	State   json.RawMessage `json:"state"`
+}
+
+// MarshalSession Encodes the session: the last step, the last update and the data of its state. The state must be
+// serializable to JSON.
+func MarshalSession(session Session) ([]byte, error) {
+	state, err := json.Marshal(session.GetState())
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encodedSession{
+		Version: SessionEncodingVersion,
+		UserID:  session.GetUserID(),
+		ChatID:  session.GetChatID(),
+		State:   state,
+	})
+}
+
+// UnmarshalSession Decodes the session encoded by MarshalSession. Values of the state are decoded on access
+// by State.Load into the type of the element.
+func UnmarshalSession(data []byte) (Session, error) {
+	var encoded encodedSession
+	err := json.Unmarshal(data, &encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoded.Version != SessionEncodingVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSessionVersion, encoded.Version)
+	}
+
+	state := NewState()
+	if len(encoded.State) > 0 {
+		err = json.Unmarshal(encoded.State, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewSessionWithState(SessionKey{UserID: encoded.UserID, ChatID: encoded.ChatID}, state), nil
+}
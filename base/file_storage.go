@@ -0,0 +1,92 @@
+package base
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	fileStorageExt      = ".json"
+	fileStorageDirPerm  = 0o700
+	fileStorageFilePerm = 0o600
+)
+
+// NewFileStorage Creates the storage keeping each session in its own file in the directory. Files are replaced
+// atomically, so a crash never leaves a partially written session. The directory is created if it doesn't exist.
+func NewFileStorage(dir string) (Storage, error) {
+	err := os.MkdirAll(dir, fileStorageDirPerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileStorage{
+		dir: dir,
+	}, nil
+}
+
+type fileStorage struct {
+	dir  string
+	lock sync.RWMutex
+}
+
+func (s *fileStorage) Load(key SessionKey) (Session, error) {
+	s.lock.RLock()
+	data, err := ioutil.ReadFile(s.path(key))
+	s.lock.RUnlock()
+
+	if os.IsNotExist(err) {
+		return NewSession(key), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalSession(data)
+}
+
+func (s *fileStorage) Store(session Session) error {
+	data, err := MarshalSession(session)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	f, err := ioutil.TempFile(s.dir, ".session-*")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(f.Name(), fileStorageFilePerm)
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(session.GetKey()))
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *fileStorage) path(key SessionKey) string {
+	return filepath.Join(s.dir, key.String()+fileStorageExt)
+}
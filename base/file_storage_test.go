@@ -0,0 +1,93 @@
+package base_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/s-larionov/telegram-api/base"
+)
+
+func newFileStorage(t *testing.T) (base.Storage, string) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "sessions")
+	if err != nil {
+		t.Fatalf("unable to create the directory: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	storage, err := base.NewFileStorage(filepath.Join(dir, "nested"))
+	if err != nil {
+		t.Fatalf("unable to create the storage: %v", err)
+	}
+
+	return storage, filepath.Join(dir, "nested")
+}
+
+func TestFileStorage(t *testing.T) {
+	storage, _ := newFileStorage(t)
+
+	testStorageRoundTrip(t, storage)
+}
+
+func TestFileStorageMissingKey(t *testing.T) {
+	storage, _ := newFileStorage(t)
+
+	testStorageMissingKey(t, storage)
+}
+
+func TestFileStorageUnsupportedVersion(t *testing.T) {
+	storage, dir := newFileStorage(t)
+
+	key := base.SessionKey{UserID: 1}
+	err := ioutil.WriteFile(filepath.Join(dir, key.String()+".json"), []byte(`{"v":99,"user_id":1,"state":{}}`), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write the session: %v", err)
+	}
+
+	_, err = storage.Load(key)
+	expectUnsupportedVersion(t, err)
+}
+
+func TestFileStorageReplacesSession(t *testing.T) {
+	storage, dir := newFileStorage(t)
+
+	key := base.SessionKey{UserID: 1}
+	for _, step := range []base.StepName{"first", "second"} {
+		session := base.NewSession(key)
+		session.GetState().Set("step", string(step))
+
+		if err := storage.Store(session); err != nil {
+			t.Fatalf("unable to store the session: %v", err)
+		}
+	}
+
+	session, err := storage.Load(key)
+	if err != nil {
+		t.Fatalf("unable to load the session: %v", err)
+	}
+
+	var step string
+	if err := session.GetState().Load("step", &step); err != nil || step != "second" {
+		t.Errorf("unexpected value: got %q (%v), want %q", step, err, "second")
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unable to read the directory: %v", err)
+	}
+
+	if len(files) != 1 || files[0].Name() != key.String()+".json" {
+		names := make([]string, 0, len(files))
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+
+		t.Errorf("unexpected files in the directory: %q", names)
+	}
+}
@@ -1,6 +1,7 @@
 package base
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"sync"
@@ -18,14 +19,16 @@ var (
 )
 
 type Flow struct {
-	storage   Storage
-	steps     []Step
-	stepsLock sync.RWMutex
+	storage    Storage
+	sessionKey SessionKeyFunc
+	steps      []Step
+	stepsLock  sync.RWMutex
 }
 
 func NewFlow(storage Storage) *Flow {
 	return &Flow{
-		storage: storage,
+		storage:    storage,
+		sessionKey: SessionPerUser,
 	}
 }
 
@@ -57,23 +60,125 @@ func (f *Flow) AddStep(step Step) error {
 	return nil
 }
 
+// SetSessionKeyFunc Changes how updates are grouped into sessions. Defaults to SessionPerUser.
+func (f *Flow) SetSessionKeyFunc(fn SessionKeyFunc) {
+	f.stepsLock.Lock()
+	defer f.stepsLock.Unlock()
+
+	f.sessionKey = fn
+}
+
+// SessionKey Returns the key of the session the update belongs to.
+func (f *Flow) SessionKey(u models.Update) SessionKey {
+	f.stepsLock.RLock()
+	fn := f.sessionKey
+	f.stepsLock.RUnlock()
+
+	return fn(u)
+}
+
+func (f *Flow) loadSession(u models.Update) (Session, error) {
+	return f.storage.Load(f.SessionKey(u))
+}
+
+// OnUpdate Passes the update to the handler of its type.
+func (f *Flow) OnUpdate(u models.Update) error {
+	return f.OnUpdateContext(context.Background(), u)
+}
+
+// OnUpdateContext Passes the update to the handler of its type. The context is passed to the steps implementing
+// ContextStep.
+func (f *Flow) OnUpdateContext(ctx context.Context, u models.Update) error {
+	switch u.GetType() {
+	case models.UpdateTypeMessage:
+		return f.onMessage(ctx, u)
+	case models.UpdateTypeEditedMessage:
+		return f.onMessageEdit(ctx, u)
+	case models.UpdateTypeChannelPost:
+		return f.onChannelPost(ctx, u)
+	case models.UpdateTypeEditedChannelPost:
+		return f.onChannelPostEdit(ctx, u)
+	case models.UpdateTypeInlineQuery:
+		return f.onInlineQuery(ctx, u)
+	case models.UpdateTypeChosenInlineResult:
+		return f.onChosenInlineResult(ctx, u)
+	case models.UpdateTypeCallbackQuery:
+		return f.onCallbackQuery(ctx, u)
+	case models.UpdateTypeShippingQuery:
+		return f.onShippingQuery(ctx, u)
+	case models.UpdateTypePreCheckoutQuery:
+		return f.onPreCheckoutQuery(ctx, u)
+	case models.UpdateTypePoll:
+		return f.onPoll(ctx, u)
+	case models.UpdateTypePollAnswer:
+		return f.onPollAnswer(ctx, u)
+	default:
+	}
+
+	return ErrUnsupportedEvent
+}
+
 func (f *Flow) OnMessage(u models.Update) error {
+	return f.onMessage(context.Background(), u)
+}
+
+func (f *Flow) OnMessageEdit(u models.Update) error {
+	return f.onMessageEdit(context.Background(), u)
+}
+
+func (f *Flow) OnChannelPost(u models.Update) error {
+	return f.onChannelPost(context.Background(), u)
+}
+
+func (f *Flow) OnChannelPostEdit(u models.Update) error {
+	return f.onChannelPostEdit(context.Background(), u)
+}
+
+func (f *Flow) OnInlineQuery(u models.Update) error {
+	return f.onInlineQuery(context.Background(), u)
+}
+
+func (f *Flow) OnChosenInlineResult(u models.Update) error {
+	return f.onChosenInlineResult(context.Background(), u)
+}
+
+func (f *Flow) OnCallbackQuery(u models.Update) error {
+	return f.onCallbackQuery(context.Background(), u)
+}
+
+func (f *Flow) OnShippingQuery(u models.Update) error {
+	return f.onShippingQuery(context.Background(), u)
+}
+
+func (f *Flow) OnPreCheckoutQuery(u models.Update) error {
+	return f.onPreCheckoutQuery(context.Background(), u)
+}
+
+func (f *Flow) OnPoll(u models.Update) error {
+	return f.onPoll(context.Background(), u)
+}
+
+func (f *Flow) OnPollAnswer(u models.Update) error {
+	return f.onPollAnswer(context.Background(), u)
+}
+
+func (f *Flow) onMessage(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
-		"from_id":    u.Message.From.ID,
+		"from_id":    userID(u.Message.From),
 		"message_id": u.Message.ID,
 		"text":       u.Message.Text,
 	}).Trace("incoming message")
 
-	session, err := f.storage.Load(u.Message.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
 	if f.isRestartCommand(u) {
-		return f.Restart(session)
+		return f.restart(ctx, session)
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
 func (f *Flow) isRestartCommand(u models.Update) bool {
@@ -90,69 +195,69 @@ func (f *Flow) isRestartCommand(u models.Update) bool {
 	return true
 }
 
-func (f *Flow) OnMessageEdit(u models.Update) error {
+func (f *Flow) onMessageEdit(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
-		"from_id":    u.EditedMessage.From.ID,
+		"from_id":    userID(u.EditedMessage.From),
 		"message_id": u.EditedMessage.ID,
 		"text":       u.EditedMessage.Text,
 	}).Trace("message was edited")
 
-	session, err := f.storage.Load(u.EditedMessage.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnChannelPost(u models.Update) error {
+func (f *Flow) onChannelPost(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
-		"from_id": u.ChannelPost.From.ID,
+		"from_id": userID(u.ChannelPost.From),
 		"chat_id": u.ChannelPost.Chat.ID,
 		"post_id": u.ChannelPost.ID,
 		"text":    u.ChannelPost.Text,
 	}).Trace("incoming post to the channel")
 
-	session, err := f.storage.Load(u.ChannelPost.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnChannelPostEdit(u models.Update) error {
+func (f *Flow) onChannelPostEdit(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
-		"from_id": u.EditedChannelPost.From.ID,
+		"from_id": userID(u.EditedChannelPost.From),
 		"chat_id": u.EditedChannelPost.Chat.ID,
 		"post_id": u.EditedChannelPost.ID,
 		"text":    u.EditedChannelPost.Text,
 	}).Trace("channel post was updated")
 
-	session, err := f.storage.Load(u.EditedChannelPost.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnInlineQuery(u models.Update) error {
+func (f *Flow) onInlineQuery(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
 		"from_id":  u.InlineQuery.From.ID,
 		"query_id": u.InlineQuery.ID,
 		"query":    u.InlineQuery.Query,
 	}).Trace("incoming inline query")
 
-	session, err := f.storage.Load(u.InlineQuery.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnChosenInlineResult(u models.Update) error {
+func (f *Flow) onChosenInlineResult(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
 		"from_id":           u.ChosenInlineResult.From.ID,
 		"query":             u.ChosenInlineResult.Query,
@@ -160,32 +265,37 @@ func (f *Flow) OnChosenInlineResult(u models.Update) error {
 		"result_id":         u.ChosenInlineResult.ID,
 	}).Trace("inline result was chosen")
 
-	session, err := f.storage.Load(u.ChosenInlineResult.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnCallbackQuery(u models.Update) error {
-	log.WithFields(log.Fields{
-		"from_id":      u.CallbackQuery.From.ID,
-		"message_id":   u.CallbackQuery.Message.ID,
-		"message_text": u.CallbackQuery.Message.Text,
-		"chat":         u.CallbackQuery.ChatInstance,
-		"query_data":   u.CallbackQuery.Data,
-	}).Trace("incoming callback query")
-
-	session, err := f.storage.Load(u.CallbackQuery.From.ID)
+func (f *Flow) onCallbackQuery(ctx context.Context, u models.Update) error {
+	fields := log.Fields{
+		"from_id":         u.CallbackQuery.From.ID,
+		"chat":            u.CallbackQuery.ChatInstance,
+		"query_data":      u.CallbackQuery.Data,
+		"game_short_name": u.CallbackQuery.GameShortName,
+	}
+	// callback queries from messages sent via the bot in inline mode (e.g. games) don't have a message
+	if u.CallbackQuery.Message != nil {
+		fields["message_id"] = u.CallbackQuery.Message.ID
+		fields["message_text"] = u.CallbackQuery.Message.Text
+	}
+	log.WithFields(fields).Trace("incoming callback query")
+
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnShippingQuery(u models.Update) error {
+func (f *Flow) onShippingQuery(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
 		"from_id": u.ShippingQuery.From.ID,
 		"invoice": u.ShippingQuery.InvoicePayload,
@@ -193,15 +303,15 @@ func (f *Flow) OnShippingQuery(u models.Update) error {
 		"address": u.ShippingQuery.ShippingAddress.String(),
 	}).Trace("incoming shipping query")
 
-	session, err := f.storage.Load(u.ShippingQuery.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnPreCheckoutQuery(u models.Update) error {
+func (f *Flow) onPreCheckoutQuery(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
 		"from_id":  u.PreCheckoutQuery.From.ID,
 		"invoice":  u.PreCheckoutQuery.InvoicePayload,
@@ -210,44 +320,52 @@ func (f *Flow) OnPreCheckoutQuery(u models.Update) error {
 		"currency": u.PreCheckoutQuery.Currency,
 	}).Trace("incoming pre checkout query")
 
-	session, err := f.storage.Load(u.PreCheckoutQuery.From.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnPoll(u models.Update) error {
+func (f *Flow) onPoll(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
 		"poll_id":   u.Poll.ID,
 		"poll_type": u.Poll.Type,
 	}).Trace("incoming poll")
 
-	session, err := f.storage.Load(0) // "zero-session" is a system's session
+	// polls have neither a user nor a chat, so by default they go to the system session
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
-func (f *Flow) OnPollAnswer(u models.Update) error {
+func (f *Flow) onPollAnswer(ctx context.Context, u models.Update) error {
 	log.WithFields(log.Fields{
 		"user_id":    u.PollAnswer.User.ID,
 		"poll_id":    u.PollAnswer.PollID,
 		"option_ids": u.PollAnswer.OptionIDs,
 	}).Trace("incoming poll answer")
 
-	session, err := f.storage.Load(u.PollAnswer.User.ID)
+	session, err := f.loadSession(u)
 	if err != nil {
 		return err
 	}
 
-	return f.Process(session, u)
+	return f.ProcessContext(ctx, session, u)
 }
 
 func (f *Flow) Process(session Session, u models.Update) error {
+	return f.ProcessContext(context.Background(), session, u)
+}
+
+// ProcessContext Processes the update by the step supporting it. The context is passed to the steps implementing
+// ContextStep. If the context is done before the step is left or after the update is processed, ctx.Err() is returned
+// and the session isn't stored.
+func (f *Flow) ProcessContext(ctx context.Context, session Session, u models.Update) error {
 	state := session.GetState()
 
 	step, err := f.findStep(session, u)
@@ -262,17 +380,26 @@ func (f *Flow) Process(session Session, u models.Update) error {
 			return err
 		}
 
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		err = finishedStep.OnLeave(session, u)
 		if err != nil {
 			return err
 		}
 	}
 
-	err = f.process(step, session, u)
+	err = f.process(ctx, step, session, u)
 	if err != nil {
 		return err
 	}
 
+	// steps which don't receive the context may finish after the timeout, their result is dropped
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	err = f.storage.Store(session)
 	if err != nil {
 		return err
@@ -281,8 +408,14 @@ func (f *Flow) Process(session Session, u models.Update) error {
 	return nil
 }
 
-func (f *Flow) process(step Step, session Session, u models.Update) error {
-	result := step.Process(session, u)
+func (f *Flow) process(ctx context.Context, step Step, session Session, u models.Update) error {
+	var result StepResult
+	if s, ok := step.(ContextStep); ok {
+		result = s.ProcessContext(ctx, session, u)
+	} else {
+		result = step.Process(session, u)
+	}
+
 	if result.Error != nil {
 		return result.Error
 	}
@@ -292,13 +425,17 @@ func (f *Flow) process(step Step, session Session, u models.Update) error {
 	}
 
 	if result.Action.Has(ResultActionRestart) {
-		return f.Restart(session)
+		return f.restart(ctx, session)
 	}
 
 	return nil
 }
 
 func (f *Flow) Restart(session Session) error {
+	return f.restart(context.Background(), session)
+}
+
+func (f *Flow) restart(ctx context.Context, session Session) error {
 	state := session.GetState()
 
 	stepName, u := state.GetLastStep()
@@ -307,11 +444,15 @@ func (f *Flow) Restart(session Session) error {
 		return err
 	}
 
-	err = f.process(step, session, u)
+	err = f.process(ctx, step, session, u)
 	if err != nil {
 		return err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	err = f.storage.Store(session)
 	if err != nil {
 		return err
@@ -355,3 +496,13 @@ func (f *Flow) findStepByName(name StepName) (Step, error) {
 
 	return nil, ErrUnsupportedEvent
 }
+
+// userID Returns the identifier of the user or zero, since senders of channel posts and of messages on behalf of chats
+// are unknown
+func userID(user *models.User) int64 {
+	if user == nil {
+		return 0
+	}
+
+	return user.ID
+}
@@ -0,0 +1,359 @@
+package flowtest
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/base"
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/telegramtest"
+)
+
+var ErrNoBotMessage = errors.New("flowtest: the bot hasn't sent any message to press the button under")
+
+// Conversation Drives a Flow with a scripted conversation between the user and the bot. API calls of the steps
+// go to the fake Bot API server, so no network is needed.
+//
+// Build steps with the API of the conversation and pass them to Build, or assign the flow created with Storage
+// to Flow directly.
+type Conversation struct {
+	// The fake Bot API server receiving the calls of the steps
+	Server *telegramtest.Server
+
+	// API client connected to Server
+	API *telegram.API
+
+	// Storage of the sessions
+	Storage base.Storage
+
+	// The flow under test
+	Flow *base.Flow
+
+	// The user talking to the bot
+	User models.User
+
+	// The chat the user is talking in
+	Chat models.Chat
+
+	lock      sync.Mutex
+	messageID int64
+	queryID   int64
+	callsFrom int
+	steps     []base.StepName
+}
+
+func New() *Conversation {
+	server := telegramtest.NewServer()
+
+	return &Conversation{
+		Server:  server,
+		API:     server.API(),
+		Storage: base.NewInMemoryStorage(),
+		User: models.User{
+			ID:        1,
+			FirstName: "John",
+			LastName:  "Doe",
+			Username:  "john_doe",
+		},
+		Chat: models.Chat{
+			ID:        1,
+			Type:      models.ChatTypePrivate,
+			FirstName: "John",
+			LastName:  "Doe",
+			Username:  "john_doe",
+		},
+	}
+}
+
+// Build Creates the flow with the steps.
+func (c *Conversation) Build(steps ...base.Step) error {
+	flow, err := base.NewFlowWithSteps(c.Storage, steps)
+	if err != nil {
+		return err
+	}
+
+	c.Flow = flow
+
+	return nil
+}
+
+// Close Shuts down the fake Bot API server.
+func (c *Conversation) Close() {
+	c.Server.Close()
+}
+
+// Send Passes the update to the flow and remembers the step the session ended up in.
+func (c *Conversation) Send(u models.Update) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.callsFrom = len(c.Server.Calls())
+
+	err := c.Flow.OnUpdate(u)
+
+	c.steps = append(c.steps, c.step())
+
+	return err
+}
+
+// SendText Sends the text message from the user. Texts starting with a slash are marked as bot commands.
+func (c *Conversation) SendText(text string) error {
+	msg := c.newMessage()
+	msg.Text = text
+
+	if strings.HasPrefix(text, "/") {
+		command := strings.SplitN(text, " ", 2)[0]
+		msg.Entities = []*models.MessageEntity{
+			{
+				Type:   models.MessageEntityTypeBotCommand,
+				Length: int32(len([]rune(command))),
+			},
+		}
+	}
+
+	return c.Send(models.Update{Message: msg})
+}
+
+// PressButton Presses the inline keyboard button with the callback data under the last message sent by the bot.
+// Returns ErrNoBotMessage if the bot hasn't sent any message yet.
+func (c *Conversation) PressButton(data string) error {
+	messageID, ok := c.botMessageID()
+	if !ok {
+		return ErrNoBotMessage
+	}
+
+	bot := c.Server.Bot
+
+	c.lock.Lock()
+	c.queryID++
+	query := &models.CallbackQuery{
+		ID:           strconv.FormatInt(c.queryID, 10),
+		From:         c.user(),
+		ChatInstance: strconv.FormatInt(c.Chat.ID, 10),
+		Data:         data,
+		Message: &models.Message{
+			ID:        messageID,
+			From:      &bot,
+			Timestamp: time.Now().Unix(),
+			Chat:      c.chat(),
+		},
+	}
+	c.lock.Unlock()
+
+	return c.Send(models.Update{CallbackQuery: query})
+}
+
+// ShareContact Sends the contact of the user with the phone number, as the “request contact” button does.
+func (c *Conversation) ShareContact(phoneNumber string) error {
+	msg := c.newMessage()
+	msg.Contact = &models.Contact{
+		PhoneNumber: phoneNumber,
+		FirstName:   c.User.FirstName,
+		LastName:    c.User.LastName,
+		UserID:      c.User.ID,
+	}
+
+	return c.Send(models.Update{Message: msg})
+}
+
+// Session Returns the session the messages of the user in the chat belong to.
+func (c *Conversation) Session() base.Session {
+	c.lock.Lock()
+	key := c.sessionKey()
+	c.lock.Unlock()
+
+	session, err := c.Storage.Load(key)
+	if err != nil {
+		return nil
+	}
+
+	return session
+}
+
+// Step Returns the step the session of the user is in.
+func (c *Conversation) Step() base.StepName {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.step()
+}
+
+// Steps Returns the steps the session was in after each update of the conversation.
+func (c *Conversation) Steps() []base.StepName {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	steps := make([]base.StepName, len(c.steps))
+	copy(steps, c.steps)
+
+	return steps
+}
+
+// Calls Returns API calls made while processing the last update.
+func (c *Conversation) Calls() []telegramtest.Call {
+	c.lock.Lock()
+	from := c.callsFrom
+	c.lock.Unlock()
+
+	calls := c.Server.Calls()
+	if from > len(calls) {
+		return nil
+	}
+
+	return calls[from:]
+}
+
+// ExpectStep Fails the test if the session isn't in the step.
+func (c *Conversation) ExpectStep(t testing.TB, want base.StepName) {
+	t.Helper()
+
+	if got := c.Step(); got != want {
+		t.Errorf("unexpected step: got %q, want %q", got, want)
+	}
+}
+
+// ExpectSteps Fails the test if the conversation didn't go through the steps.
+func (c *Conversation) ExpectSteps(t testing.TB, want ...base.StepName) {
+	t.Helper()
+
+	got := c.Steps()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected steps: got %q, want %q", got, want)
+	}
+}
+
+// ExpectState Fails the test if the state field of the session doesn't equal the value.
+func (c *Conversation) ExpectState(t testing.TB, field string, want interface{}) {
+	t.Helper()
+
+	session := c.Session()
+	if session == nil {
+		t.Errorf("unable to load the session of user %d in chat %d", c.User.ID, c.Chat.ID)
+		return
+	}
+
+	got, ok := session.GetState().Get(field)
+	if !ok {
+		t.Errorf("state field %q is not set", field)
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected value of state field %q: got %#v, want %#v", field, got, want)
+	}
+}
+
+// ExpectCall Fails the test if the method wasn't called while processing the last update. Returns the last call
+// of the method.
+func (c *Conversation) ExpectCall(t testing.TB, method string) telegramtest.Call {
+	t.Helper()
+
+	calls := c.Calls()
+	for i := len(calls) - 1; i >= 0; i-- {
+		if calls[i].Method == method {
+			return calls[i]
+		}
+	}
+
+	t.Errorf("method %s wasn't called", method)
+
+	return telegramtest.Call{}
+}
+
+// ExpectNoCalls Fails the test if any API method was called while processing the last update.
+func (c *Conversation) ExpectNoCalls(t testing.TB) {
+	t.Helper()
+
+	if calls := c.Calls(); len(calls) > 0 {
+		methods := make([]string, 0, len(calls))
+		for _, call := range calls {
+			methods = append(methods, call.Method)
+		}
+
+		t.Errorf("unexpected calls: %s", strings.Join(methods, ", "))
+	}
+}
+
+// botMessageID Returns the identifier of the message returned by the last sending method, e.g. sendMessage.
+func (c *Conversation) botMessageID() (int64, bool) {
+	calls := c.Server.Calls()
+	for i := len(calls) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(calls[i].Method, "send") {
+			continue
+		}
+
+		switch msg := calls[i].Result.(type) {
+		case models.Message:
+			return msg.ID, true
+		case *models.Message:
+			if msg != nil {
+				return msg.ID, true
+			}
+		default:
+		}
+	}
+
+	return 0, false
+}
+
+func (c *Conversation) newMessage() *models.Message {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.messageID++
+
+	return &models.Message{
+		ID:        c.messageID,
+		From:      c.user(),
+		Timestamp: time.Now().Unix(),
+		Chat:      c.chat(),
+	}
+}
+
+func (c *Conversation) step() base.StepName {
+	session, err := c.Storage.Load(c.sessionKey())
+	if err != nil {
+		return base.StepNone
+	}
+
+	step, _ := session.GetState().GetLastStep()
+
+	return step
+}
+
+func (c *Conversation) sessionKey() base.SessionKey {
+	u := models.Update{
+		Message: &models.Message{
+			From: c.user(),
+			Chat: c.chat(),
+		},
+	}
+
+	if c.Flow == nil {
+		return base.SessionPerUser(u)
+	}
+
+	return c.Flow.SessionKey(u)
+}
+
+func (c *Conversation) user() *models.User {
+	user := c.User
+
+	return &user
+}
+
+func (c *Conversation) chat() *models.Chat {
+	chat := c.Chat
+
+	return &chat
+}
@@ -0,0 +1,167 @@
+package flowtest_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/base"
+	"github.com/s-larionov/telegram-api/base/flowtest"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+// askStep Greets the user and asks to confirm with the inline keyboard button.
+type askStep struct {
+	base.StepBase
+}
+
+func (s *askStep) Supports(_ base.Session, u models.Update) bool {
+	return u.Message != nil && u.Message.Text == "/start"
+}
+
+func (s *askStep) Process(session base.Session, u models.Update) base.StepResult {
+	_, err := s.API.SendMessage(context.Background(), models.MessageRequest{
+		MessageRequestBase: models.MessageRequestBase{ChatID: strconv.FormatInt(session.GetChatID(), 10)},
+		Text:               "Hello",
+	})
+	if err != nil {
+		return base.NewStepResult(err)
+	}
+
+	_, err = s.API.SendMessage(context.Background(), models.MessageRequest{
+		MessageRequestBase: models.MessageRequestBase{
+			ChatID: strconv.FormatInt(session.GetChatID(), 10),
+			ReplyMarkup: models.NewInlineKeyboardMarkupReply([][]models.InlineKeyboardButton{
+				{{Text: "Yes", CallbackData: "yes"}},
+			}),
+		},
+		Text: "Continue?",
+	})
+
+	return base.NewStepResult(err)
+}
+
+// confirmStep Answers the pressed button and asks for the contact of the user.
+type confirmStep struct {
+	base.StepBase
+	messageID int64
+}
+
+func (s *confirmStep) Supports(_ base.Session, u models.Update) bool {
+	return u.CallbackQuery != nil && u.CallbackQuery.Data == "yes"
+}
+
+func (s *confirmStep) Process(session base.Session, u models.Update) base.StepResult {
+	s.messageID = u.CallbackQuery.Message.ID
+
+	err := s.API.AnswerCallbackQuery(context.Background(), models.AnswerCallbackQuery{
+		CallbackQueryID: u.CallbackQuery.ID,
+	})
+	if err != nil {
+		return base.NewStepResult(err)
+	}
+
+	_, err = s.API.SendMessage(context.Background(), models.MessageRequest{
+		MessageRequestBase: models.MessageRequestBase{
+			ChatID: strconv.FormatInt(session.GetChatID(), 10),
+			ReplyMarkup: models.NewKeyboardMarkupReply([][]models.KeyboardButton{
+				{models.NewKeyboardButton("Share the phone number", true, false, nil)},
+			}, true, true),
+		},
+		Text: "Share your phone number",
+	})
+
+	return base.NewStepResult(err)
+}
+
+// contactStep Remembers the shared phone number.
+type contactStep struct {
+	base.StepBase
+}
+
+func (s *contactStep) Supports(_ base.Session, u models.Update) bool {
+	return u.Message != nil && u.Message.Contact != nil
+}
+
+func (s *contactStep) Process(session base.Session, u models.Update) base.StepResult {
+	session.GetState().Set("phone", u.Message.Contact.PhoneNumber)
+
+	return base.NewStepResult(nil)
+}
+
+func newSteps(api *telegram.API) (*askStep, *confirmStep, *contactStep) {
+	ask := &askStep{StepBase: base.NewStepBase("ask", api)}
+
+	confirm := &confirmStep{StepBase: base.NewStepBase("confirm", api)}
+	confirm.AllowFrom("ask")
+
+	contact := &contactStep{StepBase: base.NewStepBase("contact", api)}
+	contact.AllowFrom("confirm")
+
+	return ask, confirm, contact
+}
+
+func TestConversation(t *testing.T) {
+	c := flowtest.New()
+	defer c.Close()
+
+	ask, confirm, contact := newSteps(c.API)
+	if err := c.Build(ask, confirm, contact); err != nil {
+		t.Fatalf("unable to build the flow: %v", err)
+	}
+
+	if err := c.SendText("/start"); err != nil {
+		t.Fatalf("unable to send the text: %v", err)
+	}
+
+	c.ExpectStep(t, "ask")
+	question := c.ExpectCall(t, "sendMessage")
+	if question.Params["text"] != "Continue?" {
+		t.Errorf("unexpected text of the last message: %v", question.Params["text"])
+	}
+
+	sent, ok := question.Result.(models.Message)
+	if !ok {
+		t.Fatalf("unexpected result of sendMessage: %#v", question.Result)
+	}
+
+	if err := c.PressButton("yes"); err != nil {
+		t.Fatalf("unable to press the button: %v", err)
+	}
+
+	// the button is under the message of the bot, not under the last message of the user
+	if confirm.messageID != sent.ID {
+		t.Errorf("unexpected message of the button: got %d, want %d", confirm.messageID, sent.ID)
+	}
+
+	answer := c.ExpectCall(t, "answerCallbackQuery")
+	if answer.Params["callback_query_id"] == "" {
+		t.Error("the callback query isn't answered")
+	}
+	c.ExpectCall(t, "sendMessage")
+
+	if err := c.ShareContact("+100"); err != nil {
+		t.Fatalf("unable to share the contact: %v", err)
+	}
+
+	c.ExpectNoCalls(t)
+	c.ExpectState(t, "phone", "+100")
+	c.ExpectSteps(t, "ask", "confirm", "contact")
+}
+
+func TestConversationPressButtonWithoutBotMessage(t *testing.T) {
+	c := flowtest.New()
+	defer c.Close()
+
+	ask, confirm, contact := newSteps(c.API)
+	if err := c.Build(ask, confirm, contact); err != nil {
+		t.Fatalf("unable to build the flow: %v", err)
+	}
+
+	if err := c.PressButton("yes"); err != flowtest.ErrNoBotMessage {
+		t.Errorf("unexpected error: got %v, want %v", err, flowtest.ErrNoBotMessage)
+	}
+
+	c.ExpectSteps(t)
+}
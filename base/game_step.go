@@ -0,0 +1,53 @@
+package base
+
+import (
+	"context"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+// GameURLFunc Returns the URL which opens the game for the user pressed the callback_game button.
+type GameURLFunc func(session Session, query *models.CallbackQuery) (string, error)
+
+// GameStep Answers callback queries from callback_game buttons of the game with the game URL.
+// The step doesn't change the last step of the session. Empty ShortName matches any game.
+type GameStep struct {
+	StepBase
+	ShortName string
+	URL       GameURLFunc
+}
+
+func NewGameStep(name StepName, api *telegram.API, shortName string, url GameURLFunc) *GameStep {
+	return &GameStep{
+		StepBase:  NewStepBase(name, api),
+		ShortName: shortName,
+		URL:       url,
+	}
+}
+
+func (s *GameStep) Supports(_ Session, u models.Update) bool {
+	if u.CallbackQuery == nil || u.CallbackQuery.GameShortName == "" {
+		return false
+	}
+
+	return s.ShortName == "" || u.CallbackQuery.GameShortName == s.ShortName
+}
+
+func (s *GameStep) Process(session Session, u models.Update) StepResult {
+	return s.ProcessContext(context.Background(), session, u)
+}
+
+func (s *GameStep) ProcessContext(ctx context.Context, session Session, u models.Update) StepResult {
+	url, err := s.URL(session, u.CallbackQuery)
+	if err != nil {
+		return NewStepResult(err, ResultActionSkipState)
+	}
+
+	err = s.API.AnswerCallbackQuery(ctx, models.AnswerCallbackQuery{
+		CallbackQueryID: u.CallbackQuery.ID,
+		URL:             url,
+	})
+
+	return NewStepResult(err, ResultActionSkipState)
+}
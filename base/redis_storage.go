@@ -0,0 +1,70 @@
+package base
+
+import (
+	"time"
+)
+
+const defaultRedisKeyPrefix = "telegram:session:"
+
+// RedisClient Commands of a Redis client used by the storage. redis.Client implements it, clients of other libraries
+// can be adapted easily.
+type RedisClient interface {
+	// Get Returns the value of the key, or nil without an error if the key doesn't exist
+	Get(key string) ([]byte, error)
+
+	// Set Sets the value of the key. The key expires after the ttl if it is positive
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// RedisStorageOptions Settings of the Redis storage.
+type RedisStorageOptions struct {
+	// Prefix of the session keys. Defaults to "telegram:session:".
+	KeyPrefix string
+
+	// Sessions which weren't updated for this time are forgotten. Sessions are kept forever if it is zero.
+	TTL time.Duration
+}
+
+// NewRedisStorage Creates the storage keeping sessions in Redis, so that they survive restarts and are shared
+// between replicas of the bot.
+func NewRedisStorage(client RedisClient, opts RedisStorageOptions) Storage {
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = defaultRedisKeyPrefix
+	}
+
+	return &redisStorage{
+		client: client,
+		opts:   opts,
+	}
+}
+
+type redisStorage struct {
+	client RedisClient
+	opts   RedisStorageOptions
+}
+
+func (s *redisStorage) Load(key SessionKey) (Session, error) {
+	data, err := s.client.Get(s.key(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return NewSession(key), nil
+	}
+
+	return UnmarshalSession(data)
+}
+
+func (s *redisStorage) Store(session Session) error {
+	data, err := MarshalSession(session)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(s.key(session.GetKey()), data, s.opts.TTL)
+}
+
+func (s *redisStorage) key(key SessionKey) string {
+	return s.opts.KeyPrefix + key.String()
+}
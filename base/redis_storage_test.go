@@ -0,0 +1,98 @@
+package base_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api/base"
+	"github.com/s-larionov/telegram-api/redis"
+	"github.com/s-larionov/telegram-api/redis/redistest"
+)
+
+func newRedisClient(t *testing.T) (*redistest.Server, *redis.Client) {
+	t.Helper()
+
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("unable to start the fake Redis server: %v", err)
+	}
+
+	client := server.Client()
+
+	t.Cleanup(func() {
+		_ = client.Close()
+		server.Close()
+	})
+
+	return server, client
+}
+
+func TestRedisStorage(t *testing.T) {
+	server, client := newRedisClient(t)
+
+	testStorageRoundTrip(t, base.NewRedisStorage(client, base.RedisStorageOptions{}))
+
+	if _, ok := server.Get("telegram:session:chat_-100_user_1"); !ok {
+		t.Errorf("the session isn't stored by the default key, keys: %q", server.Keys())
+	}
+}
+
+func TestRedisStorageMissingKey(t *testing.T) {
+	_, client := newRedisClient(t)
+
+	testStorageMissingKey(t, base.NewRedisStorage(client, base.RedisStorageOptions{}))
+}
+
+func TestRedisStorageUnsupportedVersion(t *testing.T) {
+	_, client := newRedisClient(t)
+
+	err := client.Set("sessions:user_1", []byte(`{"v":99,"user_id":1,"state":{}}`), 0)
+	if err != nil {
+		t.Fatalf("unable to write the session: %v", err)
+	}
+
+	storage := base.NewRedisStorage(client, base.RedisStorageOptions{KeyPrefix: "sessions:"})
+
+	_, err = storage.Load(base.SessionKey{UserID: 1})
+	expectUnsupportedVersion(t, err)
+}
+
+func TestRedisStorageTTL(t *testing.T) {
+	server, client := newRedisClient(t)
+
+	storage := base.NewRedisStorage(client, base.RedisStorageOptions{TTL: time.Hour})
+
+	key := base.SessionKey{UserID: 1}
+	session := base.NewSession(key)
+	session.GetState().Set("name", "John")
+
+	if err := storage.Store(session); err != nil {
+		t.Fatalf("unable to store the session: %v", err)
+	}
+
+	if ttl, ok := server.TTL("telegram:session:user_1"); !ok || ttl != time.Hour {
+		t.Errorf("unexpected ttl of the session: got %s (%v), want %s", ttl, ok, time.Hour)
+	}
+
+	server.FastForward(time.Hour - time.Minute)
+
+	loaded, err := storage.Load(key)
+	if err != nil {
+		t.Fatalf("unable to load the session: %v", err)
+	}
+
+	if _, ok := loaded.GetState().Get("name"); !ok {
+		t.Error("the session expired too early")
+	}
+
+	server.FastForward(time.Minute)
+
+	loaded, err = storage.Load(key)
+	if err != nil {
+		t.Fatalf("unable to load the session: %v", err)
+	}
+
+	if _, ok := loaded.GetState().Get("name"); ok {
+		t.Error("the session didn't expire")
+	}
+}
@@ -5,26 +5,46 @@ import (
 )
 
 type Session interface {
+	GetKey() SessionKey
+
+	// GetUserID Returns the user the session belongs to, or zero if the session is shared by all members of the chat
 	GetUserID() int64
+
+	// GetChatID Returns the chat the session belongs to, or zero if the session is shared by all chats of the user
+	GetChatID() int64
+
 	GetState() State
 	UpdateState(state State)
 }
 
 type session struct {
-	state  State
-	lock   sync.RWMutex
-	userID int64
+	state State
+	lock  sync.RWMutex
+	key   SessionKey
+}
+
+func NewSession(key SessionKey) Session {
+	return NewSessionWithState(key, NewState())
 }
 
-func NewSession(userID int64) Session {
+// NewSessionWithState Creates the session with the state restored from a storage.
+func NewSessionWithState(key SessionKey, state State) Session {
 	return &session{
-		state:  NewState(),
-		userID: userID,
+		state: state,
+		key:   key,
 	}
 }
 
+func (s *session) GetKey() SessionKey {
+	return s.key
+}
+
 func (s *session) GetUserID() int64 {
-	return s.userID
+	return s.key.UserID
+}
+
+func (s *session) GetChatID() int64 {
+	return s.key.ChatID
 }
 
 func (s *session) GetState() State {
@@ -0,0 +1,125 @@
+package base
+
+import (
+	"strconv"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+// SessionKey Identifies the session. Zero fields aren't part of the key, e.g. sessions shared by all members
+// of a chat have zero UserID. The zero key identifies the system session used for updates without a user and a chat,
+// such as polls.
+type SessionKey struct {
+	UserID int64
+	ChatID int64
+}
+
+// String Returns the key in the form suitable for file names and Redis keys, e.g. "user_1", "chat_-100_user_1".
+func (k SessionKey) String() string {
+	switch {
+	case k.UserID != 0 && k.ChatID != 0:
+		return "chat_" + strconv.FormatInt(k.ChatID, 10) + "_user_" + strconv.FormatInt(k.UserID, 10)
+	case k.ChatID != 0:
+		return "chat_" + strconv.FormatInt(k.ChatID, 10)
+	case k.UserID != 0:
+		return "user_" + strconv.FormatInt(k.UserID, 10)
+	default:
+	}
+
+	return "system"
+}
+
+// SessionKeyFunc Returns the key of the session the update belongs to.
+type SessionKeyFunc func(u models.Update) SessionKey
+
+// SessionPerUser Each user has one session for all chats. Channel posts, which have no sender, share the session
+// of the channel. It is the default of the Flow.
+func SessionPerUser(u models.Update) SessionKey {
+	if user := UpdateUser(u); user != nil {
+		return SessionKey{UserID: user.ID}
+	}
+
+	if chat := UpdateChat(u); chat != nil {
+		return SessionKey{ChatID: chat.ID}
+	}
+
+	return SessionKey{}
+}
+
+// SessionPerChat All members of the chat share one session. Updates without a chat, e.g. inline queries, belong
+// to the session of the user.
+func SessionPerChat(u models.Update) SessionKey {
+	if chat := UpdateChat(u); chat != nil {
+		return SessionKey{ChatID: chat.ID}
+	}
+
+	if user := UpdateUser(u); user != nil {
+		return SessionKey{UserID: user.ID}
+	}
+
+	return SessionKey{}
+}
+
+// SessionPerUserInChat Each user has a separate session in each chat. Updates without a chat belong to the session
+// of the user, updates without a user to the session of the chat.
+func SessionPerUserInChat(u models.Update) SessionKey {
+	var key SessionKey
+
+	if user := UpdateUser(u); user != nil {
+		key.UserID = user.ID
+	}
+
+	if chat := UpdateChat(u); chat != nil {
+		key.ChatID = chat.ID
+	}
+
+	return key
+}
+
+// UpdateUser Returns the user who caused the update, or nil if there is no such user, e.g. for channel posts.
+func UpdateUser(u models.Update) *models.User {
+	switch {
+	case u.Message != nil:
+		return u.Message.From
+	case u.EditedMessage != nil:
+		return u.EditedMessage.From
+	case u.ChannelPost != nil:
+		return u.ChannelPost.From
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost.From
+	case u.InlineQuery != nil:
+		return u.InlineQuery.From
+	case u.ChosenInlineResult != nil:
+		return u.ChosenInlineResult.From
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From
+	case u.ShippingQuery != nil:
+		return u.ShippingQuery.From
+	case u.PreCheckoutQuery != nil:
+		return u.PreCheckoutQuery.From
+	case u.PollAnswer != nil:
+		return u.PollAnswer.User
+	default:
+	}
+
+	return nil
+}
+
+// UpdateChat Returns the chat the update came from, or nil if it isn't related to a chat, e.g. for inline queries.
+func UpdateChat(u models.Update) *models.Chat {
+	switch {
+	case u.Message != nil:
+		return u.Message.Chat
+	case u.EditedMessage != nil:
+		return u.EditedMessage.Chat
+	case u.ChannelPost != nil:
+		return u.ChannelPost.Chat
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost.Chat
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		return u.CallbackQuery.Message.Chat
+	default:
+	}
+
+	return nil
+}
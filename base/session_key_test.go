@@ -0,0 +1,126 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/s-larionov/telegram-api/base"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+func TestSessionKeys(t *testing.T) {
+	user := &models.User{ID: 7}
+	group := &models.Chat{ID: -100, Type: models.ChatTypeGroup}
+	channel := &models.Chat{ID: -200, Type: models.ChatTypeChannel}
+
+	cases := []struct {
+		name          string
+		update        models.Update
+		user          *models.User
+		chat          *models.Chat
+		perUser       base.SessionKey
+		perChat       base.SessionKey
+		perUserInChat base.SessionKey
+	}{
+		{
+			name:          "message",
+			update:        models.Update{Message: &models.Message{From: user, Chat: group}},
+			user:          user,
+			chat:          group,
+			perUser:       base.SessionKey{UserID: 7},
+			perChat:       base.SessionKey{ChatID: -100},
+			perUserInChat: base.SessionKey{UserID: 7, ChatID: -100},
+		},
+		{
+			name:          "edited message",
+			update:        models.Update{EditedMessage: &models.Message{From: user, Chat: group}},
+			user:          user,
+			chat:          group,
+			perUser:       base.SessionKey{UserID: 7},
+			perChat:       base.SessionKey{ChatID: -100},
+			perUserInChat: base.SessionKey{UserID: 7, ChatID: -100},
+		},
+		{
+			name:          "channel post",
+			update:        models.Update{ChannelPost: &models.Message{Chat: channel}},
+			chat:          channel,
+			perUser:       base.SessionKey{ChatID: -200},
+			perChat:       base.SessionKey{ChatID: -200},
+			perUserInChat: base.SessionKey{ChatID: -200},
+		},
+		{
+			name: "callback query",
+			update: models.Update{CallbackQuery: &models.CallbackQuery{
+				From:    user,
+				Message: &models.Message{Chat: group},
+			}},
+			user:          user,
+			chat:          group,
+			perUser:       base.SessionKey{UserID: 7},
+			perChat:       base.SessionKey{ChatID: -100},
+			perUserInChat: base.SessionKey{UserID: 7, ChatID: -100},
+		},
+		{
+			// buttons under messages sent via inline mode come without the message
+			name:          "inline callback query",
+			update:        models.Update{CallbackQuery: &models.CallbackQuery{From: user}},
+			user:          user,
+			perUser:       base.SessionKey{UserID: 7},
+			perChat:       base.SessionKey{UserID: 7},
+			perUserInChat: base.SessionKey{UserID: 7},
+		},
+		{
+			name:          "inline query",
+			update:        models.Update{InlineQuery: &models.InlineQuery{From: user}},
+			user:          user,
+			perUser:       base.SessionKey{UserID: 7},
+			perChat:       base.SessionKey{UserID: 7},
+			perUserInChat: base.SessionKey{UserID: 7},
+		},
+		{
+			name:   "poll",
+			update: models.Update{Poll: &models.Poll{ID: "1"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := base.UpdateUser(tc.update); got != tc.user {
+				t.Errorf("unexpected user: got %v, want %v", got, tc.user)
+			}
+
+			if got := base.UpdateChat(tc.update); got != tc.chat {
+				t.Errorf("unexpected chat: got %v, want %v", got, tc.chat)
+			}
+
+			if got := base.SessionPerUser(tc.update); got != tc.perUser {
+				t.Errorf("unexpected key per user: got %+v, want %+v", got, tc.perUser)
+			}
+
+			if got := base.SessionPerChat(tc.update); got != tc.perChat {
+				t.Errorf("unexpected key per chat: got %+v, want %+v", got, tc.perChat)
+			}
+
+			if got := base.SessionPerUserInChat(tc.update); got != tc.perUserInChat {
+				t.Errorf("unexpected key per user in chat: got %+v, want %+v", got, tc.perUserInChat)
+			}
+		})
+	}
+}
+
+func TestSessionKeyString(t *testing.T) {
+	cases := []struct {
+		key  base.SessionKey
+		want string
+	}{
+		{key: base.SessionKey{UserID: 1}, want: "user_1"},
+		{key: base.SessionKey{ChatID: -100}, want: "chat_-100"},
+		{key: base.SessionKey{UserID: 1, ChatID: -100}, want: "chat_-100_user_1"},
+		{key: base.SessionKey{}, want: "system"},
+	}
+
+	for _, tc := range cases {
+		if got := tc.key.String(); got != tc.want {
+			t.Errorf("unexpected string of %+v: got %q, want %q", tc.key, got, tc.want)
+		}
+	}
+}
@@ -1,37 +1,81 @@
 package base
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/s-larionov/telegram-api/models"
 )
 
+// stateEncodingVersion Version of the encoded state. Version 1 kept plain JSON values without codecs.
+const stateEncodingVersion = 2
+
 var (
 	ErrFieldNotFound        = errors.New("field doesn't exist")
-	ErrElementMustBePointer = errors.New("element must be pointer")
-	ErrUnableToSetPointer   = errors.New("unable to set pointer")
+	ErrElementMustBePointer = errors.New("element must be non-nil pointer")
+	ErrTypeMismatch         = errors.New("type of the value doesn't match the element")
 )
 
 type State interface {
 	SetLastStep(step StepName, u models.Update)
 	GetLastStep() (StepName, models.Update)
+
+	// Set Sets the value of the field. The value is encoded by JSONCodec when the state is stored
 	Set(field string, value interface{})
+
+	// SetWithCodec Sets the value of the field which is encoded by the codec when the state is stored
+	SetWithCodec(field string, value interface{}, codec Codec)
+
+	// Get Returns the value of the field. Values restored from a storage are returned as decoded by the codec into
+	// interface{}, e.g. JSON objects become map[string]interface{}, use Load to get the original type
 	Get(field string) (value interface{}, ok bool)
+
+	// Load Stores the value of the field in the element, which must be a non-nil pointer. Returns ErrFieldNotFound
+	// if the field isn't set and ErrTypeMismatch if the value can't be assigned to the element
 	Load(field string, element interface{}) error
+
+	Delete(field string)
+
+	// Fields Returns the sorted names of the set fields
+	Fields() []string
+}
+
+// stateValue The value of the field. It is either set by a step or restored from a storage in the encoded form.
+type stateValue struct {
+	value   interface{}
+	encoded []byte
+	decoded bool
+	codec   Codec
 }
 
 type state struct {
-	data   map[string]interface{}
+	data   map[string]*stateValue
 	lock   sync.RWMutex
 	step   StepName
 	update models.Update
 }
 
+// stateJSON The encoded form of the state
+type stateJSON struct {
+	Version int                        `json:"v,omitempty"`
+	Step    StepName                   `json:"step"`
+	Update  models.Update              `json:"update"`
+	Data    map[string]json.RawMessage `json:"data,omitempty"`
+}
+
+// encodedValue The encoded form of the state value. Values encoded by JSONCodec are kept as is, the others as base64
+type encodedValue struct {
+	Codec string          `json:"codec"`
+	Value json.RawMessage `json:"value"`
+}
+
 func NewState() State {
 	return &state{
-		data: make(map[string]interface{}),
+		data: make(map[string]*stateValue),
 		step: StepNone,
 	}
 }
@@ -52,57 +96,246 @@ func (s *state) GetLastStep() (StepName, models.Update) {
 }
 
 func (s *state) Set(field string, value interface{}) {
+	s.SetWithCodec(field, value, JSONCodec)
+}
+
+func (s *state) SetWithCodec(field string, value interface{}, codec Codec) {
+	if codec == nil {
+		codec = JSONCodec
+	}
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.data[field] = value
+	s.data[field] = &stateValue{
+		value:   value,
+		decoded: true,
+		codec:   codec,
+	}
 }
 
 func (s *state) Get(field string) (value interface{}, ok bool) {
 	s.lock.RLock()
-	defer s.lock.RUnlock()
+	v, ok := s.data[field]
+	s.lock.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
 
-	value, ok = s.data[field]
+	if v.decoded {
+		return v.value, true
+	}
 
-	return
+	if err := v.codec.Unmarshal(v.encoded, &value); err != nil {
+		return nil, false
+	}
+
+	return value, true
 }
 
 func (s *state) Load(field string, element interface{}) error {
+	rv := reflect.ValueOf(element)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("state field %q: %w, got %T", field, ErrElementMustBePointer, element)
+	}
+
 	s.lock.RLock()
-	value, ok := s.data[field]
+	v, ok := s.data[field]
 	s.lock.RUnlock()
+
 	if !ok {
-		return ErrFieldNotFound
+		return fmt.Errorf("state field %q: %w", field, ErrFieldNotFound)
 	}
 
-	iv := reflect.ValueOf(value)
-	rv := reflect.ValueOf(element)
+	if !v.decoded {
+		err := v.codec.Unmarshal(v.encoded, element)
+		if err != nil {
+			return fmt.Errorf("state field %q: unable to decode %s value into %T: %w", field, v.codec.Name(), element, err)
+		}
 
-	if rv.Type().Kind() != reflect.Ptr {
-		return ErrElementMustBePointer
+		return nil
 	}
 
-	rv = rv.Elem()
-	rt := rv.Type()
+	if !assign(rv.Elem(), v.value) {
+		return fmt.Errorf("state field %q: %w: %T can't be loaded into %T", field, ErrTypeMismatch, v.value, element)
+	}
 
-	if iv.Type().Kind() != reflect.Ptr {
-		rv.Set(iv.Elem())
-		return nil
+	return nil
+}
+
+func (s *state) Delete(field string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.data, field)
+}
+
+func (s *state) Fields() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	fields := make([]string, 0, len(s.data))
+	for field := range s.data {
+		fields = append(fields, field)
 	}
 
-	if rt.Kind() != reflect.Ptr {
-		rv.Set(iv.Elem())
+	sort.Strings(fields)
 
-		return nil
+	return fields
+}
+
+func (s *state) MarshalJSON() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	encoded := stateJSON{
+		Version: stateEncodingVersion,
+		Step:    s.step,
+		Update:  s.update,
+		Data:    make(map[string]json.RawMessage, len(s.data)),
+	}
+
+	for field, v := range s.data {
+		data := v.encoded
+		if v.decoded {
+			var err error
+			data, err = v.codec.Marshal(v.value)
+			if err != nil {
+				return nil, fmt.Errorf("state field %q: unable to encode %T using %s codec: %w", field, v.value, v.codec.Name(), err)
+			}
+		}
+
+		value := json.RawMessage(data)
+		if v.codec.Name() != CodecJSON {
+			var err error
+			value, err = json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		raw, err := json.Marshal(encodedValue{
+			Codec: v.codec.Name(),
+			Value: value,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoded.Data[field] = raw
+	}
+
+	return json.Marshal(encoded)
+}
+
+func (s *state) UnmarshalJSON(data []byte) error {
+	var encoded stateJSON
+	err := json.Unmarshal(data, &encoded)
+	if err != nil {
+		return err
 	}
 
-	// pointer inside pointer case
-	iv = iv.Elem()
-	if !iv.CanAddr() {
-		return ErrUnableToSetPointer
+	values := make(map[string]*stateValue, len(encoded.Data))
+	for field, raw := range encoded.Data {
+		v, err := decodeStateValue(encoded.Version, raw)
+		if err != nil {
+			return fmt.Errorf("state field %q: %w", field, err)
+		}
+
+		values[field] = v
 	}
 
-	rv.Set(iv.Addr())
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.step = encoded.Step
+	s.update = encoded.Update
+	s.data = values
 
 	return nil
 }
+
+func decodeStateValue(version int, raw json.RawMessage) (*stateValue, error) {
+	// the first version kept plain JSON values
+	if version < stateEncodingVersion {
+		return &stateValue{
+			encoded: raw,
+			codec:   JSONCodec,
+		}, nil
+	}
+
+	var encoded encodedValue
+	err := json.Unmarshal(raw, &encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := codecByName(encoded.Codec)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &stateValue{
+		encoded: encoded.Value,
+		codec:   codec,
+	}
+
+	if encoded.Codec != CodecJSON {
+		err = json.Unmarshal(encoded.Value, &v.encoded)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// assign Sets the value to the target. Pointers are dereferenced and numbers are converted to the type
+// of the target, as JSON decoding does for the stored values.
+func assign(target reflect.Value, value interface{}) bool {
+	if value == nil {
+		switch target.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			target.Set(reflect.Zero(target.Type()))
+
+			return true
+		default:
+		}
+
+		return false
+	}
+
+	iv := reflect.ValueOf(value)
+	if iv.Type().AssignableTo(target.Type()) {
+		target.Set(iv)
+
+		return true
+	}
+
+	if iv.Kind() == reflect.Ptr && !iv.IsNil() && iv.Elem().Type().AssignableTo(target.Type()) {
+		target.Set(iv.Elem())
+
+		return true
+	}
+
+	if isNumber(iv.Kind()) && isNumber(target.Kind()) {
+		target.Set(iv.Convert(target.Type()))
+
+		return true
+	}
+
+	return false
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package base
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	CodecJSON = "json"
+	CodecGob  = "gob"
+)
+
+var ErrUnknownCodec = errors.New("unknown state codec")
+
+var (
+	// JSONCodec Encodes state values to JSON. It is used by State.Set.
+	JSONCodec Codec = jsonCodec{}
+
+	// GobCodec Encodes state values using encoding/gob. Unlike JSON it keeps exact types of numbers and non-string
+	// map keys, but the encoded values aren't human-readable.
+	GobCodec Codec = gobCodec{}
+)
+
+var (
+	codecs = map[string]Codec{
+		CodecJSON: JSONCodec,
+		CodecGob:  GobCodec,
+	}
+	codecsLock sync.RWMutex
+)
+
+// Codec Encodes values of the state, so that they can be kept by persistent storages.
+type Codec interface {
+	// Name Unique name of the codec stored next to the encoded value
+	Name() string
+
+	Marshal(value interface{}) ([]byte, error)
+
+	// Unmarshal Decodes the data into the element, which is a pointer
+	Unmarshal(data []byte, element interface{}) error
+}
+
+// RegisterCodec Makes the codec available for decoding states. Codecs used by State.SetWithCodec must be registered
+// before the states are loaded from a storage.
+func RegisterCodec(codec Codec) {
+	codecsLock.Lock()
+	defer codecsLock.Unlock()
+
+	codecs[codec.Name()] = codec
+}
+
+func codecByName(name string) (Codec, error) {
+	codecsLock.RLock()
+	defer codecsLock.RUnlock()
+
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCodec, name)
+	}
+
+	return codec, nil
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return CodecJSON
+}
+
+func (jsonCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Unmarshal(data []byte, element interface{}) error {
+	return json.Unmarshal(data, element)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Name() string {
+	return CodecGob
+}
+
+func (gobCodec) Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := gob.NewEncoder(&buf).Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, element interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(element)
+}
@@ -1,6 +1,7 @@
 package base
 
 import (
+	"context"
 	"sync"
 
 	"github.com/s-larionov/telegram-api"
@@ -26,6 +27,13 @@ type Step interface {
 	Supports(Session, models.Update) bool
 }
 
+// ContextStep A step which receives the context of the update instead of being called by Process. The context
+// is canceled when the handler timeout of the Bot expires or the Bot can't finish processing on shutdown in time,
+// so it should be passed to the API calls.
+type ContextStep interface {
+	ProcessContext(ctx context.Context, session Session, u models.Update) StepResult
+}
+
 type ResultAction uint16
 
 func (ResultAction) Combine(action ...ResultAction) ResultAction {
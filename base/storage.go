@@ -5,34 +5,34 @@ import (
 )
 
 type Storage interface {
-	Load(userID int64) (Session, error)
+	Load(key SessionKey) (Session, error)
 	Store(session Session) error
 }
 
 func NewInMemoryStorage() Storage {
 	return &inMemory{
-		storage: make(map[int64]Session),
+		storage: make(map[SessionKey]Session),
 		lock:    &sync.Mutex{},
 	}
 }
 
 type inMemory struct {
-	storage map[int64]Session
+	storage map[SessionKey]Session
 	lock    sync.Locker
 }
 
-func (s *inMemory) Load(userID int64) (Session, error) {
+func (s *inMemory) Load(key SessionKey) (Session, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	session, ok := s.storage[userID]
+	session, ok := s.storage[key]
 	if ok {
 		return session, nil
 	}
 
-	session = NewSession(userID)
+	session = NewSession(key)
 
-	s.storage[userID] = session
+	s.storage[key] = session
 
 	return session, nil
 }
@@ -41,7 +41,7 @@ func (s *inMemory) Store(session Session) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.storage[session.GetUserID()] = session
+	s.storage[session.GetKey()] = session
 
 	return nil
 }
@@ -0,0 +1,120 @@
+package base_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/s-larionov/telegram-api/base"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+type profile struct {
+	Name string
+	Age  int
+}
+
+// testStorageRoundTrip Checks that the stored session is loaded back with its last step, last update and state.
+func testStorageRoundTrip(t *testing.T, storage base.Storage) {
+	t.Helper()
+
+	key := base.SessionKey{UserID: 1, ChatID: -100}
+	update := models.Update{
+		ID: 10,
+		Message: &models.Message{
+			ID:   20,
+			From: &models.User{ID: 1, FirstName: "John"},
+			Chat: &models.Chat{ID: -100, Type: models.ChatTypeSuperGroup},
+			Text: "hello",
+		},
+	}
+
+	session := base.NewSession(key)
+	session.GetState().SetLastStep("greeting", update)
+	session.GetState().Set("profile", profile{Name: "John", Age: 30})
+	session.GetState().SetWithCodec("tags", []string{"a", "b"}, base.GobCodec)
+
+	err := storage.Store(session)
+	if err != nil {
+		t.Fatalf("unable to store the session: %v", err)
+	}
+
+	loaded, err := storage.Load(key)
+	if err != nil {
+		t.Fatalf("unable to load the session: %v", err)
+	}
+
+	if loaded.GetKey() != key {
+		t.Errorf("unexpected key: got %+v, want %+v", loaded.GetKey(), key)
+	}
+
+	step, lastUpdate := loaded.GetState().GetLastStep()
+	if step != "greeting" {
+		t.Errorf("unexpected last step: got %q, want %q", step, "greeting")
+	}
+
+	if !reflect.DeepEqual(lastUpdate, update) {
+		t.Errorf("unexpected last update: got %+v, want %+v", lastUpdate, update)
+	}
+
+	var p profile
+	err = loaded.GetState().Load("profile", &p)
+	if err != nil {
+		t.Fatalf("unable to load the profile: %v", err)
+	}
+
+	if p != (profile{Name: "John", Age: 30}) {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+
+	var tags []string
+	err = loaded.GetState().Load("tags", &tags)
+	if err != nil {
+		t.Fatalf("unable to load the tags: %v", err)
+	}
+
+	if !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("unexpected tags: %q", tags)
+	}
+
+	other, err := storage.Load(base.SessionKey{UserID: 1})
+	if err != nil {
+		t.Fatalf("unable to load another session: %v", err)
+	}
+
+	if fields := other.GetState().Fields(); len(fields) != 0 {
+		t.Errorf("sessions with different keys share the state: %q", fields)
+	}
+}
+
+// testStorageMissingKey Checks that a new empty session is returned for the key which wasn't stored.
+func testStorageMissingKey(t *testing.T, storage base.Storage) {
+	t.Helper()
+
+	key := base.SessionKey{UserID: 42}
+
+	session, err := storage.Load(key)
+	if err != nil {
+		t.Fatalf("unable to load the session: %v", err)
+	}
+
+	if session.GetKey() != key {
+		t.Errorf("unexpected key: got %+v, want %+v", session.GetKey(), key)
+	}
+
+	if step, _ := session.GetState().GetLastStep(); step != base.StepNone {
+		t.Errorf("unexpected last step of the new session: %q", step)
+	}
+
+	if fields := session.GetState().Fields(); len(fields) != 0 {
+		t.Errorf("unexpected state of the new session: %q", fields)
+	}
+}
+
+func expectUnsupportedVersion(t *testing.T, err error) {
+	t.Helper()
+
+	if !errors.Is(err, base.ErrUnsupportedSessionVersion) {
+		t.Errorf("unexpected error: got %v, want %v", err, base.ErrUnsupportedSessionVersion)
+	}
+}
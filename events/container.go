@@ -2,6 +2,7 @@ package events
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/s-larionov/telegram-api/models"
 )
@@ -9,60 +10,177 @@ import (
 const subscriberChannelBufferSize = 5
 
 type Container struct {
+	// accessed atomically, must stay first for 64-bit alignment
+	dropped uint64
+
 	mutex       sync.RWMutex
-	subscribers map[models.UpdateType][]chan models.Update
+	subscribers map[models.UpdateType][]*Subscription
+	all         []*Subscription
+	defaults    SubscribeOptions
+	closed      bool
+
+	// updates being delivered by Emit
+	emitting sync.WaitGroup
 }
 
 func NewContainer() *Container {
+	return NewContainerWithOptions(SubscribeOptions{})
+}
+
+// NewContainerWithOptions Creates the container using the options for subscriptions made by Subscribe.
+func NewContainerWithOptions(defaults SubscribeOptions) *Container {
 	return &Container{
-		subscribers: make(map[models.UpdateType][]chan models.Update),
+		subscribers: make(map[models.UpdateType][]*Subscription),
+		defaults:    defaults,
 	}
 }
 
+// Subscribe Subscribes to updates of the type using the default options of the container.
 func (c *Container) Subscribe(t models.UpdateType) <-chan models.Update {
+	return c.SubscribeWithOptions(t, c.defaults).Updates()
+}
+
+// SubscribeWithOptions Subscribes to updates of the type. The subscription can be cancelled by its Unsubscribe method.
+// Subscriptions made after Close are closed right away.
+func (c *Container) SubscribeWithOptions(t models.UpdateType, opts SubscribeOptions) *Subscription {
+	s := newSubscription(c, t, false, opts.withDefaults())
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	subscribers, ok := c.subscribers[t]
-	if !ok {
-		subscribers = make([]chan models.Update, 0)
-	}
+	if c.closed {
+		s.close()
 
-	ch := make(chan models.Update, subscriberChannelBufferSize)
-	subscribers = append(subscribers, ch)
+		return s
+	}
 
-	c.subscribers[t] = subscribers
+	c.subscribers[t] = append(c.subscribers[t], s)
 
-	return ch
+	return s
 }
 
-func (c *Container) Unsubscribe(t models.UpdateType) {
+// SubscribeAll Subscribes to updates of all types. Unlike separate subscriptions to each type, the channel keeps
+// the order in which updates of different types are emitted. Subscriptions made after Close are closed right away.
+func (c *Container) SubscribeAll(opts SubscribeOptions) *Subscription {
+	s := newSubscription(c, "", true, opts.withDefaults())
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	subscribers, ok := c.subscribers[t]
-	if !ok {
-		return
-	}
+	if c.closed {
+		s.close()
 
-	for i, ch := range subscribers {
-		close(ch)
-		subscribers[i] = nil
+		return s
 	}
 
+	c.all = append(c.all, s)
+
+	return s
+}
+
+// Unsubscribe Cancels all subscriptions to updates of the type.
+func (c *Container) Unsubscribe(t models.UpdateType) {
+	c.mutex.Lock()
+	subscribers := c.subscribers[t]
 	delete(c.subscribers, t)
+	c.mutex.Unlock()
+
+	for _, s := range subscribers {
+		s.close()
+	}
 }
 
-func (c *Container) Emit(update models.Update) {
+// Emit Delivers the update to the subscribers of its type according to their overflow policies. Returns false
+// if the update wasn't accepted by any subscription, e.g. there are no subscribers or the container is closed,
+// so the transport shouldn't confirm it to Telegram. An update dropped by the overflow policy counts as accepted.
+func (c *Container) Emit(update models.Update) bool {
 	c.mutex.RLock()
-	subscribers, ok := c.subscribers[update.GetType()]
+	if c.closed {
+		c.mutex.RUnlock()
+
+		return false
+	}
+
+	subscribers := c.subscribers[update.GetType()]
+	all := c.all
+	c.emitting.Add(1)
 	c.mutex.RUnlock()
 
-	if !ok {
+	defer c.emitting.Done()
+
+	accepted := false
+	for _, s := range subscribers {
+		if s.deliver(update) {
+			accepted = true
+		}
+	}
+
+	for _, s := range all {
+		if s.deliver(update) {
+			accepted = true
+		}
+	}
+
+	return accepted
+}
+
+// Close Stops accepting updates, waits until the updates being emitted are delivered and closes all subscriptions.
+// Updates buffered by the subscriptions can still be read from their channels. The container can't be reopened,
+// use Unsubscribe of a subscription to stop receiving updates temporarily.
+func (c *Container) Close() {
+	c.mutex.Lock()
+	c.closed = true
+	c.mutex.Unlock()
+
+	c.emitting.Wait()
+
+	c.mutex.Lock()
+	subscribers, all := c.subscribers, c.all
+	c.subscribers, c.all = make(map[models.UpdateType][]*Subscription), nil
+	c.mutex.Unlock()
+
+	for _, list := range subscribers {
+		for _, s := range list {
+			s.close()
+		}
+	}
+
+	for _, s := range all {
+		s.close()
+	}
+}
+
+// Dropped Returns the number of updates dropped by all subscriptions of the container.
+func (c *Container) Dropped() uint64 {
+	return atomic.LoadUint64(&c.dropped)
+}
+
+func (c *Container) remove(s *Subscription) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if s.all {
+		c.all = without(c.all, s)
+
 		return
 	}
 
-	for _, ch := range subscribers {
-		ch <- update
+	rest := without(c.subscribers[s.t], s)
+	if len(rest) == 0 {
+		delete(c.subscribers, s.t)
+	} else {
+		c.subscribers[s.t] = rest
+	}
+}
+
+// without Returns a copy of the list without the subscription, so that snapshots taken by Emit stay intact.
+func without(subscribers []*Subscription, s *Subscription) []*Subscription {
+	rest := make([]*Subscription, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		if subscriber != s {
+			rest = append(rest, subscriber)
+		}
 	}
+
+	return rest
 }
@@ -0,0 +1,136 @@
+package events_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/s-larionov/telegram-api/events"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+func TestContainerRoutesUpdatesByType(t *testing.T) {
+	c := events.NewContainer()
+	messages := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{})
+	callbacks := c.SubscribeWithOptions(models.UpdateTypeCallbackQuery, events.SubscribeOptions{})
+
+	c.Emit(message(1))
+	c.Emit(models.Update{ID: 2, CallbackQuery: &models.CallbackQuery{ID: "2"}})
+
+	messages.Unsubscribe()
+	callbacks.Unsubscribe()
+
+	if ids := readAll(t, messages.Updates()); !reflect.DeepEqual(ids, []int64{1}) {
+		t.Errorf("unexpected messages: got %v, want [1]", ids)
+	}
+
+	if ids := readAll(t, callbacks.Updates()); !reflect.DeepEqual(ids, []int64{2}) {
+		t.Errorf("unexpected callback queries: got %v, want [2]", ids)
+	}
+}
+
+func TestSubscriptionUnsubscribe(t *testing.T) {
+	c := events.NewContainer()
+	first := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{})
+	second := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{})
+
+	c.Emit(message(1))
+
+	first.Unsubscribe()
+	first.Unsubscribe()
+
+	c.Emit(message(2))
+
+	if ids := readAll(t, first.Updates()); !reflect.DeepEqual(ids, []int64{1}) {
+		t.Errorf("unexpected updates of the cancelled subscription: got %v, want [1]", ids)
+	}
+
+	c.Unsubscribe(models.UpdateTypeMessage)
+
+	if ids := readAll(t, second.Updates()); !reflect.DeepEqual(ids, []int64{1, 2}) {
+		t.Errorf("unexpected updates of the remaining subscription: got %v, want [1 2]", ids)
+	}
+}
+
+// TestSubscriptionUnsubscribeDuringEmit Cancels subscriptions while updates are emitted, run it with -race.
+func TestSubscriptionUnsubscribeDuringEmit(t *testing.T) {
+	c := events.NewContainer()
+
+	policies := []events.OverflowPolicy{
+		events.OverflowBlock,
+		events.OverflowDropOldest,
+		events.OverflowDropNewest,
+		events.OverflowSpill,
+	}
+
+	var readers sync.WaitGroup
+	subscriptions := make([]*events.Subscription, 0, 40)
+	for i := 0; i < cap(subscriptions); i++ {
+		s := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{
+			BufferSize: 1,
+			Overflow:   policies[i%len(policies)],
+		})
+		subscriptions = append(subscriptions, s)
+
+		// half of the subscribers never read until they are cancelled
+		if i%2 == 0 {
+			readers.Add(1)
+			go func() {
+				defer readers.Done()
+
+				for range s.Updates() {
+				}
+			}()
+		}
+	}
+
+	var emitters sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		emitters.Add(1)
+		go func(from int64) {
+			defer emitters.Done()
+
+			emitRange(c, from, from+200)
+		}(int64(i * 1000))
+	}
+
+	for _, s := range subscriptions {
+		s.Unsubscribe()
+	}
+
+	emitters.Wait()
+
+	for i, s := range subscriptions {
+		if i%2 != 0 {
+			readAll(t, s.Updates())
+		}
+	}
+
+	readers.Wait()
+}
+
+func TestContainerSubscribeAllKeepsOrder(t *testing.T) {
+	c := events.NewContainer()
+	all := c.SubscribeAll(events.SubscribeOptions{Overflow: events.OverflowSpill})
+	messages := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{})
+
+	c.Emit(message(1))
+	c.Emit(models.Update{ID: 2, CallbackQuery: &models.CallbackQuery{ID: "2"}})
+	c.Emit(message(3))
+	c.Emit(models.Update{ID: 4, InlineQuery: &models.InlineQuery{ID: "4"}})
+
+	all.Unsubscribe()
+	messages.Unsubscribe()
+
+	if ids := readAll(t, all.Updates()); !reflect.DeepEqual(ids, []int64{1, 2, 3, 4}) {
+		t.Errorf("unexpected updates: got %v, want [1 2 3 4]", ids)
+	}
+
+	if ids := readAll(t, messages.Updates()); !reflect.DeepEqual(ids, []int64{1, 3}) {
+		t.Errorf("unexpected messages: got %v, want [1 3]", ids)
+	}
+
+	if all.Type() != "" {
+		t.Errorf("unexpected type of the subscription: %q", all.Type())
+	}
+}
@@ -0,0 +1,211 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+const (
+	// OverflowBlock Waits until the subscriber reads the channel. A slow subscriber stalls Emit, but no update is lost.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest Drops the oldest buffered update to make room for the new one.
+	OverflowDropOldest
+
+	// OverflowDropNewest Drops the new update if the buffer is full.
+	OverflowDropNewest
+
+	// OverflowSpill Keeps updates which don't fit into the buffer in an unbounded queue. Emit never blocks and no update
+	// is lost, at the cost of memory. Queued updates are still delivered after the subscription is cancelled, so
+	// the channel must be read until it is closed.
+	OverflowSpill
+)
+
+// OverflowPolicy Defines what happens to an update when the buffer of the subscription is full.
+type OverflowPolicy uint8
+
+// SubscribeOptions Settings of the subscription.
+type SubscribeOptions struct {
+	// Size of the channel buffer. Defaults to 5.
+	BufferSize int
+
+	// What to do when the buffer is full. Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+}
+
+func (o SubscribeOptions) withDefaults() SubscribeOptions {
+	if o.BufferSize <= 0 {
+		o.BufferSize = subscriberChannelBufferSize
+	}
+
+	return o
+}
+
+// Subscription A subscription to updates of one type or of all types.
+type Subscription struct {
+	// accessed atomically, must stay first for 64-bit alignment
+	dropped uint64
+
+	container *Container
+	t         models.UpdateType
+	all       bool
+	policy    OverflowPolicy
+	ch        chan models.Update
+
+	// held while sending to ch, so that the channel is never closed during a send
+	lock   sync.Mutex
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+
+	// updates which didn't fit into the buffer, used by OverflowSpill
+	queue  []models.Update
+	notify chan struct{}
+}
+
+func newSubscription(c *Container, t models.UpdateType, all bool, opts SubscribeOptions) *Subscription {
+	s := &Subscription{
+		container: c,
+		t:         t,
+		all:       all,
+		policy:    opts.Overflow,
+		ch:        make(chan models.Update, opts.BufferSize),
+		done:      make(chan struct{}),
+	}
+
+	if s.policy == OverflowSpill {
+		s.notify = make(chan struct{}, 1)
+		go s.pump()
+	}
+
+	return s
+}
+
+// Type Returns the type of updates the subscription receives, or an empty type for subscriptions made by SubscribeAll.
+func (s *Subscription) Type() models.UpdateType {
+	return s.t
+}
+
+// Updates Returns the channel of updates. The channel is closed when the subscription is cancelled, the updates
+// accepted before can still be read from it.
+func (s *Subscription) Updates() <-chan models.Update {
+	return s.ch
+}
+
+// Dropped Returns the number of updates dropped because the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Unsubscribe Cancels the subscription and closes its channel. It is safe to call it more than once and concurrently
+// with Emit.
+func (s *Subscription) Unsubscribe() {
+	s.container.remove(s)
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		// releases Emit blocked by OverflowBlock and the spill pump
+		close(s.done)
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.closed = true
+
+		// the spill pump delivers the queued updates and closes the channel itself
+		if s.policy != OverflowSpill {
+			close(s.ch)
+		}
+	})
+}
+
+// deliver Passes the update to the subscription according to its policy. Returns false if the subscription is
+// cancelled before the update is accepted.
+func (s *Subscription) deliver(u models.Update) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	switch s.policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.ch <- u:
+				return true
+			default:
+			}
+
+			select {
+			case <-s.ch:
+				s.drop()
+			default:
+			}
+		}
+	case OverflowDropNewest:
+		select {
+		case s.ch <- u:
+		default:
+			s.drop()
+		}
+	case OverflowSpill:
+		s.queue = append(s.queue, u)
+
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	default:
+		select {
+		case s.ch <- u:
+		case <-s.done:
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Subscription) drop() {
+	atomic.AddUint64(&s.dropped, 1)
+	atomic.AddUint64(&s.container.dropped, 1)
+}
+
+// pump Moves spilled updates to the channel in order. After the subscription is cancelled it delivers the rest
+// of the queue and closes the channel.
+func (s *Subscription) pump() {
+	defer close(s.ch)
+
+	for {
+		s.lock.Lock()
+		if len(s.queue) == 0 {
+			closed := s.closed
+			s.lock.Unlock()
+
+			if closed {
+				return
+			}
+
+			select {
+			case <-s.notify:
+			case <-s.done:
+				// the subscription is marked as closed right after done, so the queue is checked again
+			}
+
+			continue
+		}
+
+		u := s.queue[0]
+		s.queue[0] = models.Update{}
+		s.queue = s.queue[1:]
+		s.lock.Unlock()
+
+		s.ch <- u
+	}
+}
@@ -0,0 +1,162 @@
+package events_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api/events"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+func message(id int64) models.Update {
+	return models.Update{ID: id, Message: &models.Message{ID: id}}
+}
+
+// readAll Reads the updates until the channel is closed and returns their identifiers.
+func readAll(t *testing.T, updates <-chan models.Update) []int64 {
+	t.Helper()
+
+	var ids []int64
+	for {
+		select {
+		case u, ok := <-updates:
+			if !ok {
+				return ids
+			}
+
+			ids = append(ids, u.ID)
+		case <-time.After(time.Second):
+			t.Fatalf("the channel isn't closed, read %v", ids)
+		}
+	}
+}
+
+func emitRange(c *events.Container, from, to int64) {
+	for id := from; id <= to; id++ {
+		c.Emit(message(id))
+	}
+}
+
+func TestSubscriptionOverflow(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  events.OverflowPolicy
+		ids     []int64
+		dropped uint64
+	}{
+		{name: "drop oldest", policy: events.OverflowDropOldest, ids: []int64{4, 5}, dropped: 3},
+		{name: "drop newest", policy: events.OverflowDropNewest, ids: []int64{1, 2}, dropped: 3},
+		{name: "spill", policy: events.OverflowSpill, ids: []int64{1, 2, 3, 4, 5}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := events.NewContainer()
+			s := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{
+				BufferSize: 2,
+				Overflow:   tc.policy,
+			})
+
+			emitRange(c, 1, 5)
+			s.Unsubscribe()
+
+			if ids := readAll(t, s.Updates()); !reflect.DeepEqual(ids, tc.ids) {
+				t.Errorf("unexpected updates: got %v, want %v", ids, tc.ids)
+			}
+
+			if s.Dropped() != tc.dropped {
+				t.Errorf("unexpected number of dropped updates: got %d, want %d", s.Dropped(), tc.dropped)
+			}
+
+			if c.Dropped() != tc.dropped {
+				t.Errorf("unexpected number of dropped updates of the container: got %d, want %d", c.Dropped(), tc.dropped)
+			}
+		})
+	}
+}
+
+func TestSubscriptionOverflowBlock(t *testing.T) {
+	c := events.NewContainer()
+	s := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{BufferSize: 1})
+
+	c.Emit(message(1))
+
+	emitted := make(chan struct{})
+	go func() {
+		defer close(emitted)
+
+		c.Emit(message(2))
+	}()
+
+	select {
+	case <-emitted:
+		t.Fatal("Emit doesn't wait for the full buffer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if u := <-s.Updates(); u.ID != 1 {
+		t.Errorf("unexpected update: got %d, want 1", u.ID)
+	}
+
+	select {
+	case <-emitted:
+	case <-time.After(time.Second):
+		t.Fatal("Emit isn't released by the read")
+	}
+
+	if u := <-s.Updates(); u.ID != 2 {
+		t.Errorf("unexpected update: got %d, want 2", u.ID)
+	}
+
+	if s.Dropped() != 0 {
+		t.Errorf("unexpected number of dropped updates: %d", s.Dropped())
+	}
+}
+
+func TestSubscriptionUnsubscribeReleasesBlockedEmit(t *testing.T) {
+	c := events.NewContainer()
+	s := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{BufferSize: 1})
+
+	c.Emit(message(1))
+
+	emitted := make(chan struct{})
+	go func() {
+		defer close(emitted)
+
+		c.Emit(message(2))
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	s.Unsubscribe()
+
+	select {
+	case <-emitted:
+	case <-time.After(time.Second):
+		t.Fatal("Emit isn't released by Unsubscribe")
+	}
+
+	if ids := readAll(t, s.Updates()); !reflect.DeepEqual(ids, []int64{1}) {
+		t.Errorf("unexpected updates: got %v, want [1]", ids)
+	}
+}
+
+func TestSubscriptionSpillKeepsQueueOnUnsubscribe(t *testing.T) {
+	c := events.NewContainer()
+	s := c.SubscribeWithOptions(models.UpdateTypeMessage, events.SubscribeOptions{
+		BufferSize: 1,
+		Overflow:   events.OverflowSpill,
+	})
+
+	emitRange(c, 1, 10)
+	s.Unsubscribe()
+
+	ids := readAll(t, s.Updates())
+	if want := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("unexpected updates: got %v, want %v", ids, want)
+	}
+
+	if c.Dropped() != 0 {
+		t.Errorf("unexpected number of dropped updates: %d", c.Dropped())
+	}
+}
@@ -0,0 +1,175 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+// MaxInlineQueryResults No more than 50 results per query are allowed.
+const MaxInlineQueryResults = 50
+
+var (
+	ErrTooManyInlineResults  = fmt.Errorf("no more than %d results per inline query are allowed", MaxInlineQueryResults)
+	ErrDuplicateInlineResult = errors.New("inline query result identifiers must be unique")
+	ErrInvalidInlineResult   = errors.New("inline query result must embed models.InlineQueryResult")
+)
+
+// InlineResultsBuilder Builds an answer to the inline query. Results without identifiers get unique ones.
+type InlineResultsBuilder struct {
+	answer models.AnswerInlineQuery
+}
+
+func NewInlineResultsBuilder(queryID string) *InlineResultsBuilder {
+	return &InlineResultsBuilder{
+		answer: models.AnswerInlineQuery{
+			InlineQueryID: queryID,
+		},
+	}
+}
+
+// Add Appends the results to the answer.
+func (b *InlineResultsBuilder) Add(results ...models.InlineQueryResultInterface) *InlineResultsBuilder {
+	b.answer.Results = append(b.answer.Results, results...)
+
+	return b
+}
+
+// Page Adds one page of the results starting from the offset received in the inline query and sets the offset
+// of the next page. The offset must be either empty or produced by the previous page. An invalid or out of range
+// offset produces an empty page, so that the client stops requesting more results instead of receiving the first
+// page again.
+func (b *InlineResultsBuilder) Page(results []models.InlineQueryResultInterface, offset string, size int) *InlineResultsBuilder {
+	if size <= 0 || size > MaxInlineQueryResults {
+		size = MaxInlineQueryResults
+	}
+
+	start := 0
+	if offset != "" {
+		var err error
+		start, err = strconv.Atoi(offset)
+		if err != nil || start < 0 || start > len(results) {
+			b.answer.NextOffset = ""
+
+			return b
+		}
+	}
+
+	end := start + size
+	if end >= len(results) {
+		end = len(results)
+		b.answer.NextOffset = ""
+	} else {
+		b.answer.NextOffset = strconv.Itoa(end)
+	}
+
+	return b.Add(results[start:end]...)
+}
+
+// NextOffset Sets the offset that a client should send in the next query with the same text to receive more results.
+func (b *InlineResultsBuilder) NextOffset(offset string) *InlineResultsBuilder {
+	b.answer.NextOffset = offset
+
+	return b
+}
+
+// CacheTime Sets the maximum amount of time in seconds that the result may be cached on the server.
+func (b *InlineResultsBuilder) CacheTime(seconds int64) *InlineResultsBuilder {
+	b.answer.CacheTime = seconds
+
+	return b
+}
+
+// Personal Caches the results on the server side only for the user that sent the query.
+func (b *InlineResultsBuilder) Personal() *InlineResultsBuilder {
+	b.answer.IsPersonal = true
+
+	return b
+}
+
+// SwitchPM Displays a button which switches the user to a private chat with the bot and sends the bot a start message
+// with the parameter.
+func (b *InlineResultsBuilder) SwitchPM(text, parameter string) *InlineResultsBuilder {
+	b.answer.SwitchPmText = text
+	b.answer.SwitchPmParameter = parameter
+
+	return b
+}
+
+// Build Assigns identifiers to the results which don't have them and validates the answer.
+func (b *InlineResultsBuilder) Build() (models.AnswerInlineQuery, error) {
+	answer := b.answer
+
+	if len(answer.Results) > MaxInlineQueryResults {
+		return answer, ErrTooManyInlineResults
+	}
+
+	used := make(map[string]bool, len(answer.Results))
+	for _, result := range answer.Results {
+		id := result.GetID()
+		if id == "" {
+			continue
+		}
+
+		if used[id] {
+			return answer, ErrDuplicateInlineResult
+		}
+		used[id] = true
+	}
+
+	results := make([]models.InlineQueryResultInterface, len(answer.Results))
+	next := 0
+	for i, result := range answer.Results {
+		if result.GetID() != "" {
+			results[i] = result
+			continue
+		}
+
+		id := strconv.Itoa(next)
+		for used[id] {
+			next++
+			id = strconv.Itoa(next)
+		}
+		used[id] = true
+
+		withID, err := withInlineResultID(result, id)
+		if err != nil {
+			return answer, err
+		}
+		results[i] = withID
+	}
+	answer.Results = results
+
+	return answer, nil
+}
+
+// withInlineResultID Returns a copy of the result with the identifier set.
+func withInlineResultID(result models.InlineQueryResultInterface, id string) (models.InlineQueryResultInterface, error) {
+	v := reflect.ValueOf(result)
+	isPtr := v.Kind() == reflect.Ptr
+	if isPtr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, ErrInvalidInlineResult
+	}
+
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+
+	field := cp.FieldByName("ID")
+	if !field.IsValid() || field.Kind() != reflect.String || !field.CanSet() {
+		return nil, ErrInvalidInlineResult
+	}
+	field.SetString(id)
+
+	if isPtr {
+		return cp.Addr().Interface().(models.InlineQueryResultInterface), nil
+	}
+
+	return cp.Interface().(models.InlineQueryResultInterface), nil
+}
@@ -0,0 +1,148 @@
+package helpers_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/s-larionov/telegram-api/helpers"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+func article(id string) *models.InlineQueryResultArticle {
+	return &models.InlineQueryResultArticle{
+		InlineQueryResult: models.InlineQueryResult{Type: models.InlineQueryResultTypeArticle, ID: id},
+		Title:             "article " + id,
+	}
+}
+
+func articles(n int) []models.InlineQueryResultInterface {
+	results := make([]models.InlineQueryResultInterface, 0, n)
+	for i := 0; i < n; i++ {
+		results = append(results, article(strconv.Itoa(i)))
+	}
+
+	return results
+}
+
+func ids(results []models.InlineQueryResultInterface) []string {
+	res := make([]string, 0, len(results))
+	for _, result := range results {
+		res = append(res, result.GetID())
+	}
+
+	return res
+}
+
+func TestInlineResultsBuilderAssignsIDs(t *testing.T) {
+	withoutID := article("")
+	answer, err := helpers.NewInlineResultsBuilder("query").
+		Add(withoutID, article("0"), article(""), models.InlineQueryResultArticle{Title: "value"}).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if answer.InlineQueryID != "query" {
+		t.Errorf("unexpected query id: %q", answer.InlineQueryID)
+	}
+
+	// the identifiers set by the caller are kept and not reused
+	if got, want := ids(answer.Results), []string{"1", "0", "2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected identifiers: got %q, want %q", got, want)
+	}
+
+	if _, ok := answer.Results[3].(models.InlineQueryResultArticle); !ok {
+		t.Errorf("unexpected type of the result passed by value: %T", answer.Results[3])
+	}
+
+	if withoutID.ID != "" {
+		t.Errorf("the result of the caller is modified: %q", withoutID.ID)
+	}
+}
+
+func TestInlineResultsBuilderValidates(t *testing.T) {
+	cases := []struct {
+		name    string
+		results []models.InlineQueryResultInterface
+		err     error
+	}{
+		{name: "limit", results: articles(helpers.MaxInlineQueryResults)},
+		{name: "over limit", results: articles(helpers.MaxInlineQueryResults + 1), err: helpers.ErrTooManyInlineResults},
+		{
+			name:    "duplicate",
+			results: []models.InlineQueryResultInterface{article("1"), article(""), article("1")},
+			err:     helpers.ErrDuplicateInlineResult,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := helpers.NewInlineResultsBuilder("query").Add(tc.results...).Build(); err != tc.err {
+				t.Errorf("unexpected error: got %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInlineResultsBuilderPage(t *testing.T) {
+	results := articles(25)
+
+	cases := []struct {
+		name   string
+		offset string
+		size   int
+		ids    []string
+		next   string
+	}{
+		{name: "first page", offset: "", size: 10, ids: ids(results[:10]), next: "10"},
+		{name: "middle page", offset: "10", size: 10, ids: ids(results[10:20]), next: "20"},
+		{name: "last page", offset: "20", size: 10, ids: ids(results[20:])},
+		{name: "end", offset: "25", size: 10},
+		{name: "out of range", offset: "30", size: 10},
+		{name: "negative", offset: "-1", size: 10},
+		{name: "invalid", offset: "abc", size: 10},
+		{name: "default size", offset: "", size: 0, ids: ids(results)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			answer, err := helpers.NewInlineResultsBuilder("query").NextOffset("stale").Page(results, tc.offset, tc.size).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := ids(answer.Results); !reflect.DeepEqual(got, tc.ids) && (len(got) != 0 || len(tc.ids) != 0) {
+				t.Errorf("unexpected results: got %q, want %q", got, tc.ids)
+			}
+
+			if answer.NextOffset != tc.next {
+				t.Errorf("unexpected next offset: got %q, want %q", answer.NextOffset, tc.next)
+			}
+		})
+	}
+}
+
+func TestInlineResultsBuilderOptions(t *testing.T) {
+	answer, err := helpers.NewInlineResultsBuilder("query").
+		CacheTime(60).
+		Personal().
+		SwitchPM("Log in", "login").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := models.AnswerInlineQuery{
+		InlineQueryID:     "query",
+		CacheTime:         60,
+		IsPersonal:        true,
+		SwitchPmText:      "Log in",
+		SwitchPmParameter: "login",
+		Results:           []models.InlineQueryResultInterface{},
+	}
+
+	if !reflect.DeepEqual(answer, want) {
+		t.Errorf("unexpected answer: got %+v, want %+v", answer, want)
+	}
+}
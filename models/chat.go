@@ -263,5 +263,5 @@ type ChatSetPhotoRequest struct {
 	ChatID string `json:"chat_id"`
 
 	// New chat photo, uploaded using multipart/form-data
-	Photo InputFile `json:"photo"`
+	Photo *InputFile `json:"photo"`
 }
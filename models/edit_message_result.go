@@ -0,0 +1,36 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// EditMessageResult Result of the methods which return the edited Message if the message was sent by the bot,
+// and True if an inline message was edited.
+type EditMessageResult struct {
+	// Optional. The edited message, if it was sent by the bot
+	Message *Message
+
+	// True, if an inline message was edited. There is no Message in this case
+	InlineMessageEdited bool
+}
+
+func (r *EditMessageResult) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("true")) {
+		r.InlineMessageEdited = true
+		r.Message = nil
+
+		return nil
+	}
+
+	var msg Message
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		return err
+	}
+
+	r.Message = &msg
+	r.InlineMessageEdited = false
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package models
+
+// Game This object represents a game. Use BotFather to create and edit games, their short names will act
+// as unique identifiers.
+type Game struct {
+	// Title of the game
+	Title string `json:"title"`
+
+	// Description of the game
+	Description string `json:"description"`
+
+	// Photo that will be displayed in the game message in chats.
+	Photo []*PhotoSize `json:"photo"`
+
+	// Optional. Brief description of the game or high scores included in the game message. Can be automatically
+	// edited to include current high scores for the game when the bot calls setGameScore, or manually edited
+	// using editMessageText. 0-4096 characters.
+	Text string `json:"text,omitempty"`
+
+	// Optional. Special entities that appear in text, such as usernames, URLs, bot commands, etc.
+	TextEntities []*MessageEntity `json:"text_entities,omitempty"`
+
+	// Optional. Animation that will be displayed in the game message in chats. Upload via BotFather
+	Animation *Animation `json:"animation,omitempty"`
+}
+
+// CallbackGame A placeholder, currently holds no information. Use BotFather to set up your game.
+type CallbackGame struct{}
+
+// GameHighScore This object represents one row of the high scores table for a game.
+type GameHighScore struct {
+	// Position in high score table for the game
+	Position int `json:"position"`
+
+	// User
+	User *User `json:"user"`
+
+	// Score
+	Score int64 `json:"score"`
+}
+
+// GameMessageRequest Use this entity to send a game.
+type GameMessageRequest struct {
+	// Unique identifier for the target chat
+	ChatID int64 `json:"chat_id"`
+
+	// Short name of the game, serves as the unique identifier for the game. Set up your games via Botfather.
+	GameShortName string `json:"game_short_name"`
+
+	// Optional. Sends the message silently. Users will receive a notification with no sound.
+	DisableNotification bool `json:"disable_notification,omitempty"`
+
+	// Optional. If the message is a reply, ID of the original message
+	ReplyToMessageID int64 `json:"reply_to_message_id,omitempty"`
+
+	// Optional. A JSON-serialized object for an inline keyboard. If empty, one ‘Play game_title’ button will be shown.
+	// If not empty, the first button must launch the game.
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// GameScoreRequest Use this entity to set the score of the specified user in a game.
+type GameScoreRequest struct {
+	// User identifier
+	UserID int64 `json:"user_id"`
+
+	// New score, must be non-negative
+	Score int64 `json:"score"`
+
+	// Optional. Pass True, if the high score is allowed to decrease. This can be useful when fixing mistakes
+	// or banning cheaters
+	Force bool `json:"force,omitempty"`
+
+	// Optional. Pass True, if the game message should not be automatically edited to include the current scoreboard
+	DisableEditMessage bool `json:"disable_edit_message,omitempty"`
+
+	// Required if inline_message_id is not specified. Unique identifier for the target chat
+	ChatID int64 `json:"chat_id,omitempty"`
+
+	// Required if inline_message_id is not specified. Identifier of the sent message
+	MessageID int64 `json:"message_id,omitempty"`
+
+	// Required if chat_id and message_id are not specified. Identifier of the inline message
+	InlineMessageID string `json:"inline_message_id,omitempty"`
+}
+
+// GameHighScoresRequest Use this entity to get data for high score tables.
+type GameHighScoresRequest struct {
+	// Target user id
+	UserID int64 `json:"user_id"`
+
+	// Required if inline_message_id is not specified. Unique identifier for the target chat
+	ChatID int64 `json:"chat_id,omitempty"`
+
+	// Required if inline_message_id is not specified. Identifier of the sent message
+	MessageID int64 `json:"message_id,omitempty"`
+
+	// Required if chat_id and message_id are not specified. Identifier of the inline message
+	InlineMessageID string `json:"inline_message_id,omitempty"`
+}
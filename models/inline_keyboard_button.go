@@ -28,10 +28,9 @@ type InlineKeyboardButton struct {
 	// something from multiple options.
 	SwitchInlineQueryCurrentChat string `json:"switch_inline_query_current_chat,omitempty"`
 
-	// FIXME
 	// Optional. Description of the game that will be launched when the user presses the button.
 	// NOTE: This type of button must always be the first button in the first row
-	// CallbackGame *CallbackGame `json:"callback_game,omitempty"`
+	CallbackGame *CallbackGame `json:"callback_game,omitempty"`
 
 	// Optional. Specify True, to send a Pay button.
 	// NOTE: This type of button must always be the first button in the first row.
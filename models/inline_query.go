@@ -76,11 +76,12 @@ type InlineQueryResultType string
 
 type InlineQueryResultInterface interface {
 	GetType() InlineQueryResultType
+	GetID() string
 }
 
 type InlineQueryResult struct {
 	// Type of the result
-	Type InlineQueryResultType
+	Type InlineQueryResultType `json:"type"`
 
 	// Unique identifier for this result, 1-64 Bytes
 	ID string `json:"id"`
@@ -93,6 +94,10 @@ func (r InlineQueryResult) GetType() InlineQueryResultType {
 	return r.Type
 }
 
+func (r InlineQueryResult) GetID() string {
+	return r.ID
+}
+
 // InlineQueryResultArticle Represents a link to an article or web page.
 type InlineQueryResultArticle struct {
 	InlineQueryResult
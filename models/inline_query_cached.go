@@ -0,0 +1,182 @@
+package models
+
+// InlineQueryResultCachedPhoto Represents a link to a photo stored on the Telegram servers. By default, this photo will be sent
+// by the user with an optional caption. Alternatively, you can use input_message_content to send a message
+// with the specified content instead of the photo.
+type InlineQueryResultCachedPhoto struct {
+	InlineQueryResult
+
+	// A valid file identifier of the photo
+	PhotoFileID string `json:"photo_file_id"`
+
+	// Optional. Title for the result
+	Title string `json:"title,omitempty"`
+
+	// Optional. Short description of the result
+	Description string `json:"description,omitempty"`
+
+	// Optional. Caption of the photo to be sent, 0-1024 characters after entities parsing
+	Caption string `json:"caption,omitempty"`
+
+	// Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline
+	// URLs in the media caption.
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+
+	// Optional. Content of the message to be sent instead of the photo
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedGif Represents a link to an animated GIF file stored on the Telegram servers. By default, this animated
+// GIF file will be sent by the user with an optional caption. Alternatively, you can use input_message_content
+// to send a message with specified content instead of the animation.
+type InlineQueryResultCachedGif struct {
+	InlineQueryResult
+
+	// A valid file identifier for the GIF file
+	GifFileID string `json:"gif_file_id"`
+
+	// Optional. Title for the result
+	Title string `json:"title,omitempty"`
+
+	// Optional. Caption of the GIF file to be sent, 0-1024 characters after entities parsing
+	Caption string `json:"caption,omitempty"`
+
+	// Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline
+	// URLs in the media caption.
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+
+	// Optional. Content of the message to be sent instead of the GIF animation
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedMpeg4Gif Represents a link to a video animation (H.264/MPEG-4 AVC video without sound) stored
+// on the Telegram servers. By default, this animated MPEG-4 file will be sent by the user with an optional caption.
+// Alternatively, you can use input_message_content to send a message with the specified content instead
+// of the animation.
+type InlineQueryResultCachedMpeg4Gif struct {
+	InlineQueryResult
+
+	// A valid file identifier for the MP4 file
+	Mpeg4FileID string `json:"mpeg4_file_id"`
+
+	// Optional. Title for the result
+	Title string `json:"title,omitempty"`
+
+	// Optional. Caption of the MPEG-4 file to be sent, 0-1024 characters after entities parsing
+	Caption string `json:"caption,omitempty"`
+
+	// Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline
+	// URLs in the media caption.
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+
+	// Optional. Content of the message to be sent instead of the video animation
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedSticker Represents a link to a sticker stored on the Telegram servers. By default, this sticker
+// will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified
+// content instead of the sticker.
+type InlineQueryResultCachedSticker struct {
+	InlineQueryResult
+
+	// A valid file identifier of the sticker
+	StickerFileID string `json:"sticker_file_id"`
+
+	// Optional. Content of the message to be sent instead of the sticker
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedDocument Represents a link to a file stored on the Telegram servers. By default, this file will be sent
+// by the user with an optional caption. Alternatively, you can use input_message_content to send a message
+// with the specified content instead of the file.
+type InlineQueryResultCachedDocument struct {
+	InlineQueryResult
+
+	// Title for the result
+	Title string `json:"title"`
+
+	// A valid file identifier for the file
+	DocumentFileID string `json:"document_file_id"`
+
+	// Optional. Short description of the result
+	Description string `json:"description,omitempty"`
+
+	// Optional. Caption of the document to be sent, 0-1024 characters after entities parsing
+	Caption string `json:"caption,omitempty"`
+
+	// Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline
+	// URLs in the media caption.
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+
+	// Optional. Content of the message to be sent instead of the file
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVideo Represents a link to a video file stored on the Telegram servers. By default, this video file
+// will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send
+// a message with the specified content instead of the video.
+type InlineQueryResultCachedVideo struct {
+	InlineQueryResult
+
+	// A valid file identifier for the video file
+	VideoFileID string `json:"video_file_id"`
+
+	// Title for the result
+	Title string `json:"title"`
+
+	// Optional. Short description of the result
+	Description string `json:"description,omitempty"`
+
+	// Optional. Caption of the video to be sent, 0-1024 characters after entities parsing
+	Caption string `json:"caption,omitempty"`
+
+	// Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline
+	// URLs in the media caption.
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+
+	// Optional. Content of the message to be sent instead of the video
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVoice Represents a link to a voice message stored on the Telegram servers. By default, this voice
+// message will be sent by the user. Alternatively, you can use input_message_content to send a message with
+// the specified content instead of the voice message.
+type InlineQueryResultCachedVoice struct {
+	InlineQueryResult
+
+	// A valid file identifier for the voice message
+	VoiceFileID string `json:"voice_file_id"`
+
+	// Voice message title
+	Title string `json:"title"`
+
+	// Optional. Caption, 0-1024 characters after entities parsing
+	Caption string `json:"caption,omitempty"`
+
+	// Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline
+	// URLs in the media caption.
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+
+	// Optional. Content of the message to be sent instead of the voice message
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedAudio Represents a link to an MP3 audio file stored on the Telegram servers. By default, this audio
+// file will be sent by the user. Alternatively, you can use input_message_content to send a message with
+// the specified content instead of the audio.
+type InlineQueryResultCachedAudio struct {
+	InlineQueryResult
+
+	// A valid file identifier for the audio file
+	AudioFileID string `json:"audio_file_id"`
+
+	// Optional. Caption, 0-1024 characters after entities parsing
+	Caption string `json:"caption,omitempty"`
+
+	// Optional. Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline
+	// URLs in the media caption.
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+
+	// Optional. Content of the message to be sent instead of the audio
+	InputMessageContent InputMessageContentInterface `json:"input_message_content,omitempty"`
+}
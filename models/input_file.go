@@ -1,7 +1,147 @@
 package models
 
-// TODO
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var ErrInputFileNotSerializable = errors.New("file to be uploaded must be sent using multipart/form-data")
 
 // InputFile This object represents the contents of a file to be uploaded. Must be posted using multipart/form-data in the usual
-// way that files are uploaded via the browser.
-type InputFile string
+// way that files are uploaded via the browser. Files which already exist on the Telegram servers or
+// in the Internet are passed by file_id or URL as a String instead.
+//
+// Use FromPath, FromReader or FromBytes to upload a new file, and FromFileID or FromURL to reuse an existing one.
+type InputFile struct {
+	name   string
+	path   string
+	reader io.Reader
+	data   []byte
+	ref    string
+}
+
+// FromPath Uploads the local file.
+func FromPath(path string) *InputFile {
+	return &InputFile{
+		name: filepath.Base(path),
+		path: path,
+	}
+}
+
+// FromReader Uploads the content of the reader under the file name. The reader is consumed once.
+func FromReader(name string, r io.Reader) *InputFile {
+	return &InputFile{
+		name:   name,
+		reader: r,
+	}
+}
+
+// FromBytes Uploads the in-memory content under the file name.
+func FromBytes(name string, data []byte) *InputFile {
+	return &InputFile{
+		name: name,
+		data: data,
+	}
+}
+
+// FromURL Lets Telegram download the file from the Internet.
+func FromURL(url string) *InputFile {
+	return &InputFile{
+		ref: url,
+	}
+}
+
+// FromFileID Sends the file which already exists on the Telegram servers.
+func FromFileID(fileID string) *InputFile {
+	return &InputFile{
+		ref: fileID,
+	}
+}
+
+// IsUpload Reports whether the file must be uploaded using multipart/form-data.
+func (f *InputFile) IsUpload() bool {
+	return f.ref == ""
+}
+
+// Name Returns the name of the file to be uploaded.
+func (f *InputFile) Name() string {
+	return f.name
+}
+
+// Ref Returns file_id or URL of the file which doesn't have to be uploaded.
+func (f *InputFile) Ref() string {
+	return f.ref
+}
+
+// Open Returns the content of the file to be uploaded. The caller must close it.
+func (f *InputFile) Open() (io.ReadCloser, error) {
+	switch {
+	case f.path != "":
+		return os.Open(f.path)
+	case f.reader != nil:
+		if rc, ok := f.reader.(io.ReadCloser); ok {
+			return rc, nil
+		}
+
+		return ioutil.NopCloser(f.reader), nil
+	case f.data != nil:
+		return ioutil.NopCloser(bytes.NewReader(f.data)), nil
+	default:
+	}
+
+	return nil, ErrInputFileNotSerializable
+}
+
+func (f *InputFile) MarshalJSON() ([]byte, error) {
+	if f.IsUpload() {
+		return nil, ErrInputFileNotSerializable
+	}
+
+	return json.Marshal(f.ref)
+}
+
+func (f *InputFile) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &f.ref)
+}
+
+// Size Returns the size of the file to be uploaded, or -1 if it is unknown.
+func (f *InputFile) Size() int64 {
+	switch {
+	case f.path != "":
+		info, err := os.Stat(f.path)
+		if err != nil {
+			return -1
+		}
+
+		return info.Size()
+	case f.reader != nil:
+		if l, ok := f.reader.(interface{ Len() int }); ok {
+			return int64(l.Len())
+		}
+
+		return -1
+	case f.data != nil:
+		return int64(len(f.data))
+	default:
+	}
+
+	return -1
+}
+
+// CanReopen Reports whether Open can be called more than once. Files created by FromReader can be read only once.
+func (f *InputFile) CanReopen() bool {
+	return f.reader == nil
+}
+
+// FromAttachment References a file uploaded using multipart/form-data under the name in the same request
+// (“attach://<file_attach_name>”).
+func FromAttachment(name string) *InputFile {
+	return &InputFile{
+		ref: "attach://" + name,
+	}
+}
@@ -12,6 +12,15 @@ type InputMediaType string
 
 type InputMediaInterface interface {
 	GetType() InputMediaType
+
+	// GetMedia Returns the file to send
+	GetMedia() *InputFile
+
+	// GetThumb Returns the thumbnail of the file, if the media supports it
+	GetThumb() *InputFile
+
+	// WithFiles Returns a copy of the media with replaced file and thumbnail
+	WithFiles(media, thumb *InputFile) InputMediaInterface
 }
 
 // This object represents the content of a media message to be sent.
@@ -32,7 +41,7 @@ type InputMediaPhoto struct {
 	// for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload a new one using
 	// multipart/form-data under <file_attach_name> name.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Media string `json:"media"`
+	Media *InputFile `json:"media"`
 
 	// Optional. Caption of the photo to be sent, 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -44,11 +53,13 @@ type InputMediaPhoto struct {
 
 // InputMediaVideo Represents a video to be sent.
 type InputMediaVideo struct {
+	inputMedia
+
 	// File to send. Pass a file_id to send a file that exists on the Telegram servers (recommended),
 	// pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>”
 	// to upload a new one using multipart/form-data under <file_attach_name> name.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Media string `json:"media"`
+	Media *InputFile `json:"media"`
 
 	// Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported
 	// server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height
@@ -56,7 +67,7 @@ type InputMediaVideo struct {
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was
 	// uploaded using multipart/form-data under <file_attach_name>.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Thumb string `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 
 	// Optional. Caption of the video to be sent, 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -86,7 +97,7 @@ type InputMediaAnimation struct {
 	// pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>” to upload
 	// a new one using multipart/form-data under <file_attach_name> name.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Media string `json:"media"`
+	Media *InputFile `json:"media"`
 
 	// Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported
 	// server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height
@@ -94,7 +105,7 @@ type InputMediaAnimation struct {
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was
 	// uploaded using multipart/form-data under <file_attach_name>.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Thumb string `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 
 	// Optional. Caption of the video to be sent, 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -121,7 +132,7 @@ type InputMediaAudio struct {
 	// pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>”
 	// to upload a new one using multipart/form-data under <file_attach_name> name.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Media string `json:"media"`
+	Media *InputFile `json:"media"`
 
 	// Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported
 	// server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height
@@ -129,7 +140,7 @@ type InputMediaAudio struct {
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was
 	// uploaded using multipart/form-data under <file_attach_name>.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Thumb string `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 
 	// Optional. Caption of the video to be sent, 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -156,7 +167,7 @@ type InputMediaDocument struct {
 	// pass an HTTP URL for Telegram to get a file from the Internet, or pass “attach://<file_attach_name>”
 	// to upload a new one using multipart/form-data under <file_attach_name> name.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Media string `json:"media"`
+	Media *InputFile `json:"media"`
 
 	// Optional. Thumbnail of the file sent; can be ignored if thumbnail generation for the file is supported
 	// server-side. The thumbnail should be in JPEG format and less than 200 kB in size. A thumbnail‘s width and height
@@ -164,7 +175,7 @@ type InputMediaDocument struct {
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail was
 	// uploaded using multipart/form-data under <file_attach_name>.
 	// [More info on Sending Files](https://core.telegram.org/bots/api#sending-files)
-	Thumb string `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 
 	// Optional. Caption of the video to be sent, 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -173,3 +184,87 @@ type InputMediaDocument struct {
 	// or inline URLs in the media caption.
 	ParseMode ParseMode `json:"parse_mode,omitempty"`
 }
+
+func NewInputMediaPhoto(media *InputFile) InputMediaPhoto {
+	return InputMediaPhoto{
+		inputMedia: inputMedia{Type: InputMediaTypePhoto},
+		Media:      media,
+	}
+}
+
+func (m InputMediaPhoto) GetMedia() *InputFile { return m.Media }
+func (m InputMediaPhoto) GetThumb() *InputFile { return nil }
+
+func (m InputMediaPhoto) WithFiles(media, _ *InputFile) InputMediaInterface {
+	m.Media = media
+
+	return m
+}
+
+func NewInputMediaVideo(media *InputFile) InputMediaVideo {
+	return InputMediaVideo{
+		inputMedia: inputMedia{Type: InputMediaTypeVideo},
+		Media:      media,
+	}
+}
+
+func (m InputMediaVideo) GetMedia() *InputFile { return m.Media }
+func (m InputMediaVideo) GetThumb() *InputFile { return m.Thumb }
+
+func (m InputMediaVideo) WithFiles(media, thumb *InputFile) InputMediaInterface {
+	m.Media = media
+	m.Thumb = thumb
+
+	return m
+}
+
+func NewInputMediaAnimation(media *InputFile) InputMediaAnimation {
+	return InputMediaAnimation{
+		inputMedia: inputMedia{Type: InputMediaTypeAnimation},
+		Media:      media,
+	}
+}
+
+func (m InputMediaAnimation) GetMedia() *InputFile { return m.Media }
+func (m InputMediaAnimation) GetThumb() *InputFile { return m.Thumb }
+
+func (m InputMediaAnimation) WithFiles(media, thumb *InputFile) InputMediaInterface {
+	m.Media = media
+	m.Thumb = thumb
+
+	return m
+}
+
+func NewInputMediaAudio(media *InputFile) InputMediaAudio {
+	return InputMediaAudio{
+		inputMedia: inputMedia{Type: InputMediaTypeAudio},
+		Media:      media,
+	}
+}
+
+func (m InputMediaAudio) GetMedia() *InputFile { return m.Media }
+func (m InputMediaAudio) GetThumb() *InputFile { return m.Thumb }
+
+func (m InputMediaAudio) WithFiles(media, thumb *InputFile) InputMediaInterface {
+	m.Media = media
+	m.Thumb = thumb
+
+	return m
+}
+
+func NewInputMediaDocument(media *InputFile) InputMediaDocument {
+	return InputMediaDocument{
+		inputMedia: inputMedia{Type: InputMediaTypeDocument},
+		Media:      media,
+	}
+}
+
+func (m InputMediaDocument) GetMedia() *InputFile { return m.Media }
+func (m InputMediaDocument) GetThumb() *InputFile { return m.Thumb }
+
+func (m InputMediaDocument) WithFiles(media, thumb *InputFile) InputMediaInterface {
+	m.Media = media
+	m.Thumb = thumb
+
+	return m
+}
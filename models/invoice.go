@@ -0,0 +1,108 @@
+package models
+
+// Invoice This object contains basic information about an invoice.
+type Invoice struct {
+	// Product name
+	Title string `json:"title"`
+
+	// Product description
+	Description string `json:"description"`
+
+	// Unique bot deep-linking parameter that can be used to generate this invoice
+	StartParameter string `json:"start_parameter"`
+
+	// Three-letter ISO 4217 currency code
+	Currency string `json:"currency"`
+
+	// Total price in the smallest units of the currency (integer, not float/double).
+	// For example, for a price of US$ 1.45 pass amount = 145. See the exp parameter in currencies.json, it shows
+	// the number of digits past the decimal point for each currency (2 for the majority of currencies).
+	TotalAmount int64 `json:"total_amount"`
+}
+
+// LabeledPrice This object represents a portion of the price for goods or services.
+type LabeledPrice struct {
+	// Portion label
+	Label string `json:"label"`
+
+	// Price of the product in the smallest units of the currency (integer, not float/double).
+	// For example, for a price of US$ 1.45 pass amount = 145. See the exp parameter in currencies.json, it shows
+	// the number of digits past the decimal point for each currency (2 for the majority of currencies).
+	Amount int64 `json:"amount"`
+}
+
+// InvoiceMessageRequest Use this entity to send invoices.
+type InvoiceMessageRequest struct {
+	// Unique identifier for the target private chat
+	ChatID int64 `json:"chat_id"`
+
+	// Product name, 1-32 characters
+	Title string `json:"title"`
+
+	// Product description, 1-255 characters
+	Description string `json:"description"`
+
+	// Bot-defined invoice payload, 1-128 bytes. This will not be displayed to the user, use for your internal processes.
+	Payload string `json:"payload"`
+
+	// Payments provider token, obtained via Botfather
+	ProviderToken string `json:"provider_token"`
+
+	// Unique deep-linking parameter that can be used to generate this invoice when used as a start parameter
+	StartParameter string `json:"start_parameter"`
+
+	// Three-letter ISO 4217 currency code
+	Currency string `json:"currency"`
+
+	// Price breakdown, a list of components (e.g. product price, tax, discount, delivery cost, delivery tax,
+	// bonus, etc.)
+	Prices []LabeledPrice `json:"prices"`
+
+	// Optional. JSON-encoded data about the invoice, which will be shared with the payment provider.
+	// A detailed description of required fields should be provided by the payment provider.
+	ProviderData string `json:"provider_data,omitempty"`
+
+	// Optional. URL of the product photo for the invoice. Can be a photo of the goods or a marketing image
+	// for a service. People like it better when they see what they are paying for.
+	PhotoURL string `json:"photo_url,omitempty"`
+
+	// Optional. Photo size
+	PhotoSize int `json:"photo_size,omitempty"`
+
+	// Optional. Photo width
+	PhotoWidth int `json:"photo_width,omitempty"`
+
+	// Optional. Photo height
+	PhotoHeight int `json:"photo_height,omitempty"`
+
+	// Optional. Pass True, if you require the user's full name to complete the order
+	NeedName bool `json:"need_name,omitempty"`
+
+	// Optional. Pass True, if you require the user's phone number to complete the order
+	NeedPhoneNumber bool `json:"need_phone_number,omitempty"`
+
+	// Optional. Pass True, if you require the user's email address to complete the order
+	NeedEmail bool `json:"need_email,omitempty"`
+
+	// Optional. Pass True, if you require the user's shipping address to complete the order
+	NeedShippingAddress bool `json:"need_shipping_address,omitempty"`
+
+	// Optional. Pass True, if user's phone number should be sent to provider
+	SendPhoneNumberToProvider bool `json:"send_phone_number_to_provider,omitempty"`
+
+	// Optional. Pass True, if user's email address should be sent to provider
+	SendEmailToProvider bool `json:"send_email_to_provider,omitempty"`
+
+	// Optional. Pass True, if the final price depends on the shipping method
+	IsFlexible bool `json:"is_flexible,omitempty"`
+
+	// Optional. Sends the message silently. Users will receive a notification with no sound.
+	DisableNotification bool `json:"disable_notification,omitempty"`
+
+	// Optional. If the message is a reply, ID of the original message
+	ReplyToMessageID int64 `json:"reply_to_message_id,omitempty"`
+
+	// Optional. A JSON-serialized object for an inline keyboard. If empty, one 'Pay total price' button will be shown.
+	// If not empty, the first button must be a Pay button.
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
@@ -87,10 +87,9 @@ type Message struct {
 	// when this field is set, the document field will also be set
 	Animation *Animation `json:"animation,omitempty"`
 
-	// FIXME
 	// Optional. Message is a game, information about the game.
 	// https://core.telegram.org/bots/api#games
-	// Game *Game `json:"game,omitempty"`
+	Game *Game `json:"game,omitempty"`
 
 	// Optional. Message is a photo, available sizes of the photo
 	Photo []*PhotoSize `json:"photo,omitempty"`
@@ -167,23 +166,20 @@ type Message struct {
 	// reply_to_message fields even if it is itself a reply.
 	PinnedMessage *Message `json:"pinned_message,omitempty"`
 
-	// FIXME
 	// Optional. Message is an invoice for a payment, information about the invoice.
 	// https://core.telegram.org/bots/api#payments
-	// Invoice *Invoice `json:"invoice,omitempty"`
+	Invoice *Invoice `json:"invoice,omitempty"`
 
-	// FIXME
 	// Optional. Message is a service message about a successful payment, information about the payment.
 	// https://core.telegram.org/bots/api#payments
-	// SuccessfulPayment *SuccessfulPayment `json:"successful_payment,omitempty"`
+	SuccessfulPayment *SuccessfulPayment `json:"successful_payment,omitempty"`
 
 	// Optional. The domain name of the website on which the user has logged in.
 	// https://core.telegram.org/widgets/login
 	ConnectedWebsite string `json:"connected_website,omitempty"`
 
-	// FIXME
 	// Optional. Telegram Passport data
-	// PassportData *PassportData `json:"passport_data,omitempty"`
+	PassportData *PassportData `json:"passport_data,omitempty"`
 
 	// Optional. Inline keyboard attached to the message. login_url buttons are represented as ordinary url buttons.
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
@@ -265,7 +261,7 @@ type PhotoMessageRequest struct {
 	// Photo to send. Pass a file_id as String to send a photo that exists on the Telegram servers (recommended),
 	// pass an HTTP URL as a String for Telegram to get a photo from the Internet, or upload a new photo using
 	// multipart/form-data.
-	Photo InputFile `json:"photo"`
+	Photo *InputFile `json:"photo"`
 
 	// Photo caption (may also be used when resending photos by file_id), 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -279,7 +275,7 @@ type AudioMessageRequest struct {
 	// Audio file to send. Pass a file_id as String to send an audio file that exists on the Telegram servers
 	// (recommended), pass an HTTP URL as a String for Telegram to get an audio file from the Internet, or upload
 	// a new one using multipart/form-data.
-	Audio InputFile `json:"audio"`
+	Audio *InputFile `json:"audio"`
 
 	// Photo caption (may also be used when resending photos by file_id), 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -298,7 +294,7 @@ type AudioMessageRequest struct {
 	// not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail
 	// was uploaded using multipart/form-data under <file_attach_name>.
-	Thumb InputFile `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 }
 
 // DocumentMessageRequest Use this entity to send general files.
@@ -308,7 +304,7 @@ type DocumentMessageRequest struct {
 	// File to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended),
 	// pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using
 	// multipart/form-data.
-	Document InputFile `json:"document"`
+	Document *InputFile `json:"document"`
 
 	// Document caption (may also be used when resending documents by file_id), 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -318,7 +314,7 @@ type DocumentMessageRequest struct {
 	// not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail
 	// was uploaded using multipart/form-data under <file_attach_name>
-	Thumb InputFile `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 }
 
 // VideoMessageRequest Use this entity to send video files.
@@ -328,7 +324,7 @@ type VideoMessageRequest struct {
 	// Video to send. Pass a file_id as String to send a video that exists on the Telegram servers (recommended),
 	// pass an HTTP URL as a String for Telegram to get a video from the Internet, or upload a new video using
 	// multipart/form-data.
-	Video InputFile `json:"video"`
+	Video *InputFile `json:"video"`
 
 	// Video caption (may also be used when resending documents by file_id), 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -347,7 +343,7 @@ type VideoMessageRequest struct {
 	// not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail
 	// was uploaded using multipart/form-data under <file_attach_name>
-	Thumb InputFile `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 
 	// Pass True, if the uploaded video is suitable for streaming
 	SupportsStreaming bool `json:"supports_streaming,omitempty"`
@@ -360,7 +356,7 @@ type AnimationMessageRequest struct {
 	// Animation to send. Pass a file_id as String to send an animation that exists on the Telegram
 	// servers (recommended), pass an HTTP URL as a String for Telegram to get an animation from the Internet,
 	// or upload a new animation using multipart/form-data.
-	Animation InputFile `json:"animation"`
+	Animation *InputFile `json:"animation"`
 
 	// Animation caption (may also be used when resending documents by file_id), 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -379,7 +375,7 @@ type AnimationMessageRequest struct {
 	// not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail
 	// was uploaded using multipart/form-data under <file_attach_name>
-	Thumb InputFile `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 }
 
 // VideoNoteMessageRequest As of v.4.0, Telegram clients support rounded square mp4 videos of up to 1 minute long.
@@ -390,7 +386,7 @@ type VideoNoteMessageRequest struct {
 	// Video note to send. Pass a file_id as String to send a video note that exists on the Telegram
 	// servers (recommended) or upload a new video using multipart/form-data.
 	// Sending video notes by a URL is currently unsupported
-	VideoNote InputFile `json:"video_note"`
+	VideoNote *InputFile `json:"video_note"`
 
 	// Video caption (may also be used when resending documents by file_id), 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -406,7 +402,7 @@ type VideoNoteMessageRequest struct {
 	// not exceed 320. Ignored if the file is not uploaded using multipart/form-data. Thumbnails can’t be reused
 	// and can be only uploaded as a new file, so you can pass “attach://<file_attach_name>” if the thumbnail
 	// was uploaded using multipart/form-data under <file_attach_name>
-	Thumb InputFile `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 }
 
 // VoiceMessageRequest Use this method to send audio files, if you want Telegram clients to display the file as a playable voice message.
@@ -418,7 +414,7 @@ type VoiceMessageRequest struct {
 	// Audio file to send. Pass a file_id as String to send an audio file that exists on the Telegram servers
 	// (recommended), pass an HTTP URL as a String for Telegram to get an audio file from the Internet, or upload
 	// a new one using multipart/form-data.
-	Voice InputFile `json:"voice"`
+	Voice *InputFile `json:"voice"`
 
 	// Photo caption (may also be used when resending photos by file_id), 0-1024 characters after entities parsing
 	Caption string `json:"caption,omitempty"`
@@ -461,8 +457,9 @@ type LocationMessageRequest struct {
 // EditMessageLiveLocation Use this entity to edit live location messages. A location can be edited until its live_period expires
 // or editing is explicitly disabled by a call to stopMessageLiveLocation.
 type EditMessageLiveLocation struct {
-	// Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	ChatID string `json:"chat_id"`
+	// Required if inline_message_id is not specified. Unique identifier for the target chat or username of the target
+	// channel (in the format @channelusername)
+	ChatID string `json:"chat_id,omitempty"`
 
 	// Latitude of the location
 	Latitude float64 `json:"latitude"`
@@ -474,7 +471,7 @@ type EditMessageLiveLocation struct {
 	MessageID int64 `json:"message_id,omitempty"`
 
 	// Required if chat_id and message_id are not specified. Identifier of the inline message
-	InlineMessageID int64 `json:"inline_message_id,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
 
 	// Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard,
 	// instructions to remove reply keyboard or to force a reply from the user.
@@ -485,14 +482,15 @@ type EditMessageLiveLocation struct {
 // StopMessageLiveLocation Use this entity to edit live location messages. A location can be edited until its live_period expires
 // or editing is explicitly disabled by a call to stopMessageLiveLocation.
 type StopMessageLiveLocation struct {
-	// Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	ChatID string `json:"chat_id"`
+	// Required if inline_message_id is not specified. Unique identifier for the target chat or username of the target
+	// channel (in the format @channelusername)
+	ChatID string `json:"chat_id,omitempty"`
 
 	// Required if inline_message_id is not specified. Identifier of the message to edit
 	MessageID int64 `json:"message_id,omitempty"`
 
 	// Required if chat_id and message_id are not specified. Identifier of the inline message
-	InlineMessageID int64 `json:"inline_message_id,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
 
 	// Additional interface options. A JSON-serialized object for an inline keyboard, custom reply keyboard,
 	// instructions to remove reply keyboard or to force a reply from the user.
@@ -593,7 +591,7 @@ type EditMessageRequest struct {
 	MessageID int64 `json:"message_id,omitempty"`
 
 	// Required if chat_id and message_id are not specified. Identifier of the inline message
-	InlineMessageID int64 `json:"inline_message_id,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
 
 	// Send Markdown or HTML, if you want Telegram apps to show bold, italic, fixed-width text or inline URLs
 	// in your bot's message.
@@ -0,0 +1,13 @@
+package models
+
+// ShippingOption This object represents one shipping option.
+type ShippingOption struct {
+	// Shipping option identifier
+	ID string `json:"id"`
+
+	// Option title
+	Title string `json:"title"`
+
+	// List of price portions
+	Prices []LabeledPrice `json:"prices"`
+}
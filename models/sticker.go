@@ -86,7 +86,7 @@ type SendStickerRequest struct {
 	// Sticker to send. Pass a file_id as String to send a file that exists on the Telegram servers (recommended),
 	// pass an HTTP URL as a String for Telegram to get a .WEBP file from the Internet, or upload a new one
 	// using multipart/form-data.
-	Sticker InputFile `json:"sticker"`
+	Sticker *InputFile `json:"sticker"`
 }
 
 // NewStickerSetRequest Use this entity to create a new sticker set owned by a user. The bot will be able to edit the sticker
@@ -107,11 +107,11 @@ type NewStickerSetRequest struct {
 	// and either width or height must be exactly 512px. Pass a file_id as a String to send a file that already exists
 	// on the Telegram servers, pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload
 	// a new one using multipart/form-data.
-	PngSticker InputFile `json:"png_sticker,omitempty"`
+	PngSticker *InputFile `json:"png_sticker,omitempty"`
 
 	// Optional	TGS animation with the sticker, uploaded using multipart/form-data.
 	// See https://core.telegram.org/animated_stickers#technical-requirements for technical requirements
-	TgsSticker InputFile `json:"tgs_sticker,omitempty"`
+	TgsSticker *InputFile `json:"tgs_sticker,omitempty"`
 
 	// One or more emoji corresponding to the sticker
 	Emojis string `json:"emojis"`
@@ -137,11 +137,11 @@ type AddStickerToSetSetRequest struct {
 	// and either width or height must be exactly 512px. Pass a file_id as a String to send a file that already exists
 	// on the Telegram servers, pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload
 	// a new one using multipart/form-data.
-	PngSticker InputFile `json:"png_sticker,omitempty"`
+	PngSticker *InputFile `json:"png_sticker,omitempty"`
 
 	// Optional	TGS animation with the sticker, uploaded using multipart/form-data.
 	// See https://core.telegram.org/animated_stickers#technical-requirements for technical requirements
-	TgsSticker InputFile `json:"tgs_sticker,omitempty"`
+	TgsSticker *InputFile `json:"tgs_sticker,omitempty"`
 
 	// One or more emoji corresponding to the sticker
 	Emojis string `json:"emojis"`
@@ -164,5 +164,5 @@ type StickerSetThumbRequest struct {
 	// requirements. Pass a file_id as a String to send a file that already exists on the Telegram servers,
 	// pass an HTTP URL as a String for Telegram to get a file from the Internet, or upload a new one using
 	// multipart/form-data. More info on Sending Files ». Animated sticker set thumbnail can't be uploaded via HTTP URL.
-	Thumb InputFile `json:"thumb,omitempty"`
+	Thumb *InputFile `json:"thumb,omitempty"`
 }
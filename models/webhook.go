@@ -12,7 +12,7 @@ type WebhookRequest struct {
 
 	// Upload your public key certificate so that the root certificate in use can be checked
 	// See our [self-signed guide](https://core.telegram.org/bots/self-signed) for details.
-	Certificate InputFile `json:"certificate,omitempty"`
+	Certificate *InputFile `json:"certificate,omitempty"`
 
 	// Maximum allowed number of simultaneous HTTPS connections to the webhook for update delivery, 1-100.
 	// Defaults to 40. Use lower values to limit the load on your bot‘s server, and higher values
@@ -27,6 +27,11 @@ type WebhookRequest struct {
 	// Please note that this parameter doesn't affect updates created before the call to the setWebhook, so unwanted
 	// updates may be received for a short period of time.
 	AllowedUpdates []UpdateType `json:"allowed_updates,omitempty"`
+
+	// A secret token to be sent in a header “X-Telegram-Bot-Api-Secret-Token” in every webhook request, 1-256
+	// characters. Only characters A-Z, a-z, 0-9, _ and - are allowed. The header is useful to ensure that the request
+	// comes from a webhook set by you.
+	SecretToken string `json:"secret_token,omitempty"`
 }
 
 // WebhookInfo Contains information about the current status of a webhook.
@@ -0,0 +1,89 @@
+package passport
+
+import (
+	"github.com/s-larionov/telegram-api/models"
+)
+
+// Credentials Decrypted EncryptedCredentials: the payload of the authorization request and secrets required
+// to decrypt the Telegram Passport elements.
+type Credentials struct {
+	// Credentials for encrypted data
+	SecureData SecureData `json:"secure_data"`
+
+	// Bot-specified nonce. Make sure that it is the same as the one passed in the authorization request
+	Nonce string `json:"nonce"`
+}
+
+// SecureData Credentials for the Telegram Passport elements by their types.
+type SecureData map[models.PassportElementType]*SecureValue
+
+// SecureValue Credentials required to decrypt the value of the Telegram Passport element.
+type SecureValue struct {
+	// Optional. Credentials for encrypted Telegram Passport data
+	Data *DataCredentials `json:"data,omitempty"`
+
+	// Optional. Credentials for an encrypted document's front side
+	FrontSide *FileCredentials `json:"front_side,omitempty"`
+
+	// Optional. Credentials for an encrypted document's reverse side
+	ReverseSide *FileCredentials `json:"reverse_side,omitempty"`
+
+	// Optional. Credentials for an encrypted selfie of the user with a document
+	Selfie *FileCredentials `json:"selfie,omitempty"`
+
+	// Optional. Credentials for an encrypted translation of the document
+	Translation []*FileCredentials `json:"translation,omitempty"`
+
+	// Optional. Credentials for encrypted files
+	Files []*FileCredentials `json:"files,omitempty"`
+}
+
+// DataCredentials These credentials can be used to decrypt encrypted data from the data field
+// in EncryptedPassportElement.
+type DataCredentials struct {
+	// Checksum of encrypted data
+	DataHash string `json:"data_hash"`
+
+	// Secret of encrypted data
+	Secret string `json:"secret"`
+}
+
+// FileCredentials These credentials can be used to decrypt encrypted files from the front_side, reverse_side, selfie,
+// files and translation fields in EncryptedPassportElement.
+type FileCredentials struct {
+	// Checksum of encrypted file
+	FileHash string `json:"file_hash"`
+
+	// Secret of encrypted file
+	Secret string `json:"secret"`
+}
+
+// PersonalDetails Decrypted data of the “personal_details” element.
+type PersonalDetails struct {
+	FirstName            string `json:"first_name"`
+	LastName             string `json:"last_name"`
+	MiddleName           string `json:"middle_name,omitempty"`
+	BirthDate            string `json:"birth_date"`
+	Gender               string `json:"gender"`
+	CountryCode          string `json:"country_code"`
+	ResidenceCountryCode string `json:"residence_country_code"`
+	FirstNameNative      string `json:"first_name_native"`
+	LastNameNative       string `json:"last_name_native"`
+	MiddleNameNative     string `json:"middle_name_native,omitempty"`
+}
+
+// ResidentialAddress Decrypted data of the “address” element.
+type ResidentialAddress struct {
+	StreetLine1 string `json:"street_line1"`
+	StreetLine2 string `json:"street_line2,omitempty"`
+	City        string `json:"city"`
+	State       string `json:"state,omitempty"`
+	CountryCode string `json:"country_code"`
+	PostCode    string `json:"post_code"`
+}
+
+// IDDocumentData Decrypted data of the “passport”, “driver_license”, “identity_card” and “internal_passport” elements.
+type IDDocumentData struct {
+	DocumentNo string `json:"document_no"`
+	ExpiryDate string `json:"expiry_date,omitempty"`
+}
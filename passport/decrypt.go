@@ -0,0 +1,148 @@
+package passport
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // Telegram Passport uses OAEP with SHA-1
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+var (
+	ErrHashMismatch   = errors.New("passport: hash of the decrypted data doesn't match")
+	ErrInvalidPadding = errors.New("passport: invalid padding of the decrypted data")
+	ErrInvalidLength  = errors.New("passport: encrypted data length is not a multiple of the block size")
+)
+
+// DecryptCredentials Decrypts the credentials with the bot's private RSA key. The secret is decrypted with RSA-OAEP,
+// then the credentials are decrypted with AES-256-CBC and verified by their hash.
+func DecryptCredentials(key *rsa.PrivateKey, credentials *models.EncryptedCredentials) (*Credentials, error) {
+	encryptedSecret, err := base64.StdEncoding.DecodeString(credentials.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := rsa.DecryptOAEP(sha1.New(), nil, key, encryptedSecret, nil) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := base64.StdEncoding.DecodeString(credentials.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(credentials.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := decrypt(secret, hash, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Credentials
+	err = json.Unmarshal(payload, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DecryptData Decrypts the base64-encoded data field of the EncryptedPassportElement.
+func DecryptData(credentials *DataCredentials, data string) ([]byte, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, hash, err := decodeSecretAndHash(credentials.Secret, credentials.DataHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(secret, hash, encrypted)
+}
+
+// DecryptDataInto Decrypts the data field of the EncryptedPassportElement and unmarshals it into v,
+// e.g. *PersonalDetails.
+func DecryptDataInto(credentials *DataCredentials, data string, v interface{}) error {
+	payload, err := DecryptData(credentials, data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, v)
+}
+
+// DecryptFile Decrypts the content of the PassportFile downloaded from the Telegram servers.
+func DecryptFile(credentials *FileCredentials, file []byte) ([]byte, error) {
+	secret, hash, err := decodeSecretAndHash(credentials.Secret, credentials.FileHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(secret, hash, file)
+}
+
+func decodeSecretAndHash(secret, hash string) ([]byte, []byte, error) {
+	s, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, h, nil
+}
+
+// decrypt Decrypts the data with AES-256-CBC. The key and IV are derived from SHA-512(secret + hash),
+// the padded plaintext must have the SHA-256 equal to the hash. The first byte of the plaintext is the padding length.
+func decrypt(secret, hash, data []byte) ([]byte, error) {
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, ErrInvalidLength
+	}
+
+	key, iv := deriveKey(secret, hash)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	padded := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(padded, data)
+
+	sum := sha256.Sum256(padded)
+	if !bytes.Equal(sum[:], hash) {
+		return nil, ErrHashMismatch
+	}
+
+	padding := int(padded[0])
+	if padding < 32 || padding > len(padded) {
+		return nil, ErrInvalidPadding
+	}
+
+	return padded[padding:], nil
+}
+
+func deriveKey(secret, hash []byte) (key, iv []byte) {
+	h := sha512.New()
+	_, _ = h.Write(secret)
+	_, _ = h.Write(hash)
+	sum := h.Sum(nil)
+
+	return sum[:32], sum[32:48]
+}
@@ -0,0 +1,226 @@
+package passport
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+var (
+	testKeyOnce sync.Once
+	testKey     *rsa.PrivateKey
+)
+
+func privateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	testKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("unable to generate the key: %v", err)
+		}
+
+		testKey = key
+	})
+
+	if testKey == nil {
+		t.Fatal("the key wasn't generated")
+	}
+
+	return testKey
+}
+
+func encode(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestDecryptCredentials(t *testing.T) {
+	key := privateKey(t)
+
+	want := &Credentials{
+		Nonce: "nonce",
+		SecureData: SecureData{
+			models.PassportElementTypePersonalDetails: {
+				Data: &DataCredentials{DataHash: "aGFzaA==", Secret: "c2VjcmV0"},
+			},
+			models.PassportElementTypePassport: {
+				FrontSide: &FileCredentials{FileHash: "ZnJvbnQ=", Secret: "c2VjcmV0"},
+				Selfie:    &FileCredentials{FileHash: "c2VsZmll", Secret: "c2VjcmV0"},
+			},
+		},
+	}
+
+	encrypted, err := EncryptCredentials(&key.PublicKey, want)
+	if err != nil {
+		t.Fatalf("unable to encrypt the credentials: %v", err)
+	}
+
+	got, err := DecryptCredentials(key, encrypted)
+	if err != nil {
+		t.Fatalf("unable to decrypt the credentials: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected credentials: got %+v, want %+v", got, want)
+	}
+}
+
+func TestDecryptCredentialsWrongKey(t *testing.T) {
+	encrypted, err := EncryptCredentials(&privateKey(t).PublicKey, &Credentials{Nonce: "nonce"})
+	if err != nil {
+		t.Fatalf("unable to encrypt the credentials: %v", err)
+	}
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate the key: %v", err)
+	}
+
+	if _, err := DecryptCredentials(other, encrypted); err == nil {
+		t.Error("credentials were decrypted with another key")
+	}
+}
+
+func TestDecryptCredentialsTampered(t *testing.T) {
+	key := privateKey(t)
+
+	encrypted, err := EncryptCredentials(&key.PublicKey, &Credentials{Nonce: "nonce"})
+	if err != nil {
+		t.Fatalf("unable to encrypt the credentials: %v", err)
+	}
+
+	data, _ := base64.StdEncoding.DecodeString(encrypted.Data)
+	data[len(data)-1] ^= 1
+	encrypted.Data = encode(data)
+
+	if _, err := DecryptCredentials(key, encrypted); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("unexpected error: got %v, want %v", err, ErrHashMismatch)
+	}
+}
+
+func TestDecryptData(t *testing.T) {
+	want := PersonalDetails{
+		FirstName:   "John",
+		LastName:    "Doe",
+		BirthDate:   "01.01.1990",
+		Gender:      "male",
+		CountryCode: "GB",
+	}
+
+	payload := []byte(`{"first_name":"John","last_name":"Doe","birth_date":"01.01.1990","gender":"male","country_code":"GB"}`)
+
+	data, secret, hash, err := Encrypt(payload)
+	if err != nil {
+		t.Fatalf("unable to encrypt the data: %v", err)
+	}
+
+	credentials := &DataCredentials{DataHash: encode(hash), Secret: encode(secret)}
+
+	var got PersonalDetails
+	err = DecryptDataInto(credentials, encode(data), &got)
+	if err != nil {
+		t.Fatalf("unable to decrypt the data: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("unexpected data: got %+v, want %+v", got, want)
+	}
+}
+
+func TestDecryptFile(t *testing.T) {
+	for _, size := range []int{0, 1, aes.BlockSize - 1, aes.BlockSize, 1000} {
+		want := bytes.Repeat([]byte{'x'}, size)
+
+		file, secret, hash, err := Encrypt(want)
+		if err != nil {
+			t.Fatalf("unable to encrypt the file of %d bytes: %v", size, err)
+		}
+
+		got, err := DecryptFile(&FileCredentials{FileHash: encode(hash), Secret: encode(secret)}, file)
+		if err != nil {
+			t.Fatalf("unable to decrypt the file of %d bytes: %v", size, err)
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("unexpected content of the file of %d bytes: got %d bytes", size, len(got))
+		}
+	}
+}
+
+func TestDecryptFileErrors(t *testing.T) {
+	file, secret, hash, err := Encrypt([]byte("content"))
+	if err != nil {
+		t.Fatalf("unable to encrypt the file: %v", err)
+	}
+
+	tampered := append([]byte(nil), file...)
+	tampered[0] ^= 1
+
+	otherSecret := append([]byte(nil), secret...)
+	otherSecret[0] ^= 1
+
+	badPadding, badPaddingHash := encryptWithPadding(t, secret, 16, []byte("0123456789abcdef"))
+
+	tests := []struct {
+		name   string
+		file   []byte
+		secret []byte
+		hash   []byte
+		want   error
+	}{
+		{name: "tampered file", file: tampered, secret: secret, hash: hash, want: ErrHashMismatch},
+		{name: "wrong secret", file: file, secret: otherSecret, hash: hash, want: ErrHashMismatch},
+		{name: "truncated file", file: file[:len(file)-1], secret: secret, hash: hash, want: ErrInvalidLength},
+		{name: "empty file", file: nil, secret: secret, hash: hash, want: ErrInvalidLength},
+		{name: "bad padding", file: badPadding, secret: secret, hash: badPaddingHash, want: ErrInvalidPadding},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentials := &FileCredentials{FileHash: encode(tt.hash), Secret: encode(tt.secret)}
+
+			if _, err := DecryptFile(credentials, tt.file); !errors.Is(err, tt.want) {
+				t.Errorf("unexpected error: got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// encryptWithPadding Encrypts the data with the padding of the given length. Paddings shorter than 32 bytes
+// are invalid, but the hash matches, so only the padding check can reject the data.
+func encryptWithPadding(t *testing.T, secret []byte, padding int, data []byte) (encrypted, hash []byte) {
+	t.Helper()
+
+	padded := make([]byte, padding+len(data))
+	padded[0] = byte(padding)
+	copy(padded[padding:], data)
+
+	if len(padded)%aes.BlockSize != 0 {
+		t.Fatalf("padded data of %d bytes isn't aligned to the block size", len(padded))
+	}
+
+	sum := sha256.Sum256(padded)
+	hash = sum[:]
+
+	key, iv := deriveKey(secret, hash)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("unable to create the cipher: %v", err)
+	}
+
+	encrypted = make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+
+	return encrypted, hash
+}
@@ -0,0 +1,77 @@
+package passport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // Telegram Passport uses OAEP with SHA-1
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+const (
+	secretSize = 32
+	minPadding = 32
+)
+
+// Encrypt Encrypts the data the same way Telegram does it. Returns the encrypted data, a random secret
+// and the hash required to decrypt it. Useful to build Telegram Passport fixtures with locally generated keys.
+func Encrypt(data []byte) (encrypted, secret, hash []byte, err error) {
+	secret = make([]byte, secretSize)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	padding := minPadding + (aes.BlockSize-(len(data)+minPadding)%aes.BlockSize)%aes.BlockSize
+	padded := make([]byte, padding+len(data))
+	_, err = rand.Read(padded[:padding])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	padded[0] = byte(padding)
+	copy(padded[padding:], data)
+
+	sum := sha256.Sum256(padded)
+	hash = sum[:]
+
+	key, iv := deriveKey(secret, hash)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	encrypted = make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+
+	return encrypted, secret, hash, nil
+}
+
+// EncryptCredentials Encrypts the credentials with the bot's public RSA key the same way Telegram does it.
+func EncryptCredentials(key *rsa.PublicKey, credentials *Credentials) (*models.EncryptedCredentials, error) {
+	payload, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, secret, hash, err := Encrypt(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedSecret, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, key, secret, nil) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EncryptedCredentials{
+		Data:   base64.StdEncoding.EncodeToString(encrypted),
+		Hash:   base64.StdEncoding.EncodeToString(hash),
+		Secret: base64.StdEncoding.EncodeToString(encryptedSecret),
+	}, nil
+}
@@ -0,0 +1,146 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/request"
+)
+
+const (
+	defaultPollingTimeout    = 30 * time.Second
+	defaultPollingMinBackoff = time.Second
+	defaultPollingMaxBackoff = time.Minute
+)
+
+// PollingOptions Settings of the long polling loop started by API.StartPolling.
+type PollingOptions struct {
+	// Timeout for long polling. Defaults to 30 seconds.
+	Timeout time.Duration
+
+	// Limits the number of updates to be retrieved per request. Values between 1—100 are accepted. Defaults to 100.
+	Limit int64
+
+	// A list of the update types you want your bot to receive. Specify an empty list to receive all updates
+	// regardless of type (default).
+	AllowedUpdates []models.UpdateType
+
+	// Offset of the first update to be requested. By default, updates starting with the earliest unconfirmed update
+	// are returned.
+	Offset int64
+
+	// Delay before the first retry after a failed request. Doubled after each consecutive failure. Defaults to 1 second.
+	MinBackoff time.Duration
+
+	// Upper bound for the delay between retries. Defaults to 1 minute.
+	MaxBackoff time.Duration
+}
+
+func (o PollingOptions) withDefaults() PollingOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = defaultPollingTimeout
+	}
+
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = defaultPollingMinBackoff
+	}
+
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = defaultPollingMaxBackoff
+	}
+
+	if o.MaxBackoff < o.MinBackoff {
+		o.MaxBackoff = o.MinBackoff
+	}
+
+	return o
+}
+
+// StartPolling Receives updates using long polling and emits them to the subscribers until the context is canceled.
+// Received updates are confirmed by the next getUpdates call, so each update is delivered once. Updates which aren't
+// accepted by any subscriber, e.g. because the bot hasn't subscribed yet, aren't confirmed and are requested again
+// after the backoff. Failed requests are retried with an exponential backoff, except for request.ErrUnauthorized
+// (wrong token) and request.ErrConflict (webhook is set up or another getUpdates is running), which are returned.
+// Returns ctx.Err() when the context is done.
+//
+// Notes
+// 1. This method will not work if an outgoing webhook is set up.
+// 2. Updates are emitted to the same subscribers as updates received by WebhookHandler.
+func (b *API) StartPolling(ctx context.Context, opts PollingOptions) error {
+	opts = opts.withDefaults()
+
+	updatesRequest := models.UpdateRequest{
+		Offset:           opts.Offset,
+		Limit:            opts.Limit,
+		TimeoutInSeconds: int64(opts.Timeout / time.Second),
+		AllowedUpdates:   opts.AllowedUpdates,
+	}
+
+	backoff := opts.MinBackoff
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		updates, err := b.GetUpdates(ctx, updatesRequest)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, request.ErrUnauthorized), errors.Is(err, request.ErrConflict):
+			// retrying doesn't help until the token or the webhook is changed
+			return err
+		case err != nil:
+			log.WithError(err).WithField("retry_in", backoff).Error("unable to get updates")
+		case b.emit(updates, &updatesRequest):
+			backoff = opts.MinBackoff
+
+			continue
+		default:
+			log.WithField("retry_in", backoff).Warn("updates aren't accepted by subscribers, they will be requested again")
+		}
+
+		if err := sleep(ctx, backoff); err != nil {
+			return err
+		}
+
+		backoff *= 2
+		if backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}
+
+// emit Emits the updates and moves the offset past the accepted ones. Stops at the first update which isn't accepted,
+// so that it isn't confirmed, and returns false.
+func (b *API) emit(updates []models.Update, updatesRequest *models.UpdateRequest) bool {
+	for _, update := range updates {
+		if !b.subscribers.Emit(update) {
+			return false
+		}
+
+		if update.ID >= updatesRequest.Offset {
+			updatesRequest.Offset = update.ID + 1
+		}
+	}
+
+	return true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,249 @@
+package telegram_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/events"
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/request"
+	"github.com/s-larionov/telegram-api/telegramtest"
+)
+
+type pollingTest struct {
+	server *telegramtest.Server
+	api    *telegram.API
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func startPolling(t *testing.T, opts telegram.PollingOptions) *pollingTest {
+	t.Helper()
+
+	server := telegramtest.NewServer()
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	if opts.Timeout == 0 {
+		opts.Timeout = time.Second
+	}
+
+	pt := &pollingTest{
+		server: server,
+		api:    server.API(),
+		cancel: cancel,
+		done:   make(chan error, 1),
+	}
+
+	go func() {
+		pt.done <- pt.api.StartPolling(ctx, opts)
+	}()
+
+	return pt
+}
+
+func (pt *pollingTest) wait(t *testing.T) error {
+	t.Helper()
+
+	select {
+	case err := <-pt.done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("the polling didn't stop")
+	}
+
+	return nil
+}
+
+func (pt *pollingTest) stop(t *testing.T) {
+	t.Helper()
+
+	pt.cancel()
+
+	if err := pt.wait(t); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func receive(t *testing.T, updates <-chan models.Update, n int) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, n)
+	for len(ids) < n {
+		select {
+		case u := <-updates:
+			ids = append(ids, u.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("updates aren't received, got %v", ids)
+		}
+	}
+
+	return ids
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func offsets(calls []telegramtest.Call) []float64 {
+	result := make([]float64, 0, len(calls))
+	for _, call := range calls {
+		offset, _ := call.Params["offset"].(float64)
+		result = append(result, offset)
+	}
+
+	return result
+}
+
+func TestStartPollingConfirmsUpdates(t *testing.T) {
+	pt := startPolling(t, telegram.PollingOptions{
+		Limit:          2,
+		AllowedUpdates: []models.UpdateType{models.UpdateTypeMessage, models.UpdateTypeCallbackQuery},
+	})
+	updates := pt.api.SubscribeAll(events.SubscribeOptions{}).Updates()
+
+	pt.server.PushUpdates(
+		models.Update{ID: 10, Message: &models.Message{ID: 1}},
+		models.Update{ID: 11, CallbackQuery: &models.CallbackQuery{ID: "2"}},
+		models.Update{ID: 12, Message: &models.Message{ID: 3}},
+	)
+
+	if ids := receive(t, updates, 3); !reflect.DeepEqual(ids, []int64{10, 11, 12}) {
+		t.Errorf("unexpected updates: got %v, want [10 11 12]", ids)
+	}
+
+	waitFor(t, "the confirmation", func() bool {
+		return pt.server.PendingUpdates() == 0
+	})
+
+	pt.stop(t)
+
+	calls := pt.server.CallsTo("getUpdates")
+	if got := offsets(calls)[:3]; !reflect.DeepEqual(got, []float64{0, 12, 13}) {
+		t.Errorf("unexpected offsets: got %v, want [0 12 13]", got)
+	}
+
+	for _, call := range calls {
+		if call.Params["limit"] != float64(2) || call.Params["timeout"] != float64(1) {
+			t.Errorf("unexpected limit or timeout: %v", call.Params)
+		}
+
+		allowed := []interface{}{"message", "callback_query"}
+		if !reflect.DeepEqual(call.Params["allowed_updates"], allowed) {
+			t.Errorf("unexpected allowed updates: got %v, want %v", call.Params["allowed_updates"], allowed)
+		}
+	}
+}
+
+func TestStartPollingKeepsUnacceptedUpdates(t *testing.T) {
+	pt := startPolling(t, telegram.PollingOptions{MinBackoff: 5 * time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	pt.server.PushUpdates(
+		models.Update{ID: 10, Message: &models.Message{ID: 1}},
+		models.Update{ID: 11, Message: &models.Message{ID: 2}},
+	)
+
+	// nobody has subscribed, so the updates are requested again without confirmation
+	waitFor(t, "repeated requests", func() bool {
+		return len(pt.server.CallsTo("getUpdates")) >= 3
+	})
+
+	if pending := pt.server.PendingUpdates(); pending != 2 {
+		t.Errorf("unexpected number of pending updates: got %d, want 2", pending)
+	}
+
+	updates := pt.api.SubscribeAll(events.SubscribeOptions{}).Updates()
+
+	if ids := receive(t, updates, 2); !reflect.DeepEqual(ids, []int64{10, 11}) {
+		t.Errorf("unexpected updates: got %v, want [10 11]", ids)
+	}
+
+	waitFor(t, "the confirmation", func() bool {
+		return pt.server.PendingUpdates() == 0
+	})
+
+	pt.stop(t)
+
+	for i, offset := range offsets(pt.server.CallsTo("getUpdates")) {
+		if offset != 0 && offset != 12 {
+			t.Errorf("unexpected offset of request %d: %v", i, offset)
+		}
+	}
+}
+
+func TestStartPollingBackoff(t *testing.T) {
+	pt := startPolling(t, telegram.PollingOptions{MinBackoff: 20 * time.Millisecond, MaxBackoff: 40 * time.Millisecond})
+	updates := pt.api.SubscribeAll(events.SubscribeOptions{}).Updates()
+
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		pt.server.RespondError("getUpdates", http.StatusInternalServerError, "Internal Server Error")
+	}
+	pt.server.PushUpdates(models.Update{ID: 10, Message: &models.Message{ID: 1}})
+
+	receive(t, updates, 1)
+
+	// 20ms after the first failure, then doubled up to the maximum: 40ms and 40ms
+	if elapsed := time.Since(started); elapsed < 100*time.Millisecond {
+		t.Errorf("the retries don't back off: the update is received in %s", elapsed)
+	}
+
+	pt.stop(t)
+
+	if n := len(pt.server.CallsTo("getUpdates")); n < 4 {
+		t.Errorf("unexpected number of requests: got %d, want at least 4", n)
+	}
+}
+
+func TestStartPollingFatalErrors(t *testing.T) {
+	cases := []struct {
+		code int
+		err  error
+	}{
+		{code: http.StatusUnauthorized, err: request.ErrUnauthorized},
+		{code: http.StatusConflict, err: request.ErrConflict},
+	}
+
+	for _, tc := range cases {
+		t.Run(http.StatusText(tc.code), func(t *testing.T) {
+			server := telegramtest.NewServer()
+			defer server.Close()
+
+			server.RespondError("getUpdates", tc.code, http.StatusText(tc.code))
+
+			done := make(chan error, 1)
+			go func() {
+				done <- server.API().StartPolling(context.Background(), telegram.PollingOptions{Timeout: time.Second})
+			}()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, tc.err) {
+					t.Errorf("unexpected error: got %v, want %v", err, tc.err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the error is retried")
+			}
+
+			if n := len(server.CallsTo("getUpdates")); n != 1 {
+				t.Errorf("unexpected number of requests: got %d, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package redis
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPoolSize = 10
+	defaultTimeout  = 5 * time.Second
+)
+
+// Options Settings of the client.
+type Options struct {
+	// TCP address of the server, e.g. "localhost:6379"
+	Addr string
+
+	// Password for AUTH. Not sent if it is empty.
+	Password string
+
+	// Database selected by SELECT after connecting.
+	DB int
+
+	// Maximum number of idle connections kept open. Defaults to 10.
+	PoolSize int
+
+	// Timeout of connecting and of each command. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.PoolSize <= 0 {
+		o.PoolSize = defaultPoolSize
+	}
+
+	if o.Timeout <= 0 {
+		o.Timeout = defaultTimeout
+	}
+
+	return o
+}
+
+// Client A minimal client of servers speaking the Redis protocol (RESP). It is safe for concurrent use.
+type Client struct {
+	opts Options
+	idle chan *conn
+	once sync.Once
+	done chan struct{}
+}
+
+func NewClient(opts Options) *Client {
+	opts = opts.withDefaults()
+
+	return &Client{
+		opts: opts,
+		idle: make(chan *conn, opts.PoolSize),
+		done: make(chan struct{}),
+	}
+}
+
+// Do Sends the command and returns the reply. Error replies are returned as Error.
+func (c *Client) Do(args ...string) (interface{}, error) {
+	cn, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(c.opts.Timeout, args...)
+	if err != nil {
+		// the state of the connection is unknown after a network error
+		_ = cn.Close()
+
+		return nil, err
+	}
+
+	c.put(cn)
+
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+
+	return reply, nil
+}
+
+// Get Returns the value of the key, or nil if the key doesn't exist.
+func (c *Client) Get(key string) ([]byte, error) {
+	reply, err := c.Do("GET", key)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply == nil {
+		return nil, nil
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, ErrProtocol
+	}
+
+	return value, nil
+}
+
+// Set Sets the value of the key. The key expires after the ttl if it is positive.
+func (c *Client) Set(key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		// Redis rejects zero expiration, so the ttl is rounded up to whole milliseconds
+		ms := (ttl + time.Millisecond - 1) / time.Millisecond
+		args = append(args, "PX", strconv.FormatInt(int64(ms), 10))
+	}
+
+	_, err := c.Do(args...)
+
+	return err
+}
+
+// Del Removes the key.
+func (c *Client) Del(key string) error {
+	_, err := c.Do("DEL", key)
+
+	return err
+}
+
+// Close Closes idle connections. Connections in use are closed when they are returned.
+func (c *Client) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+	})
+
+	for {
+		select {
+		case cn := <-c.idle:
+			_ = cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get() (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	return c.dial()
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case <-c.done:
+		_ = cn.Close()
+
+		return
+	default:
+	}
+
+	select {
+	case c.idle <- cn:
+	default:
+		_ = cn.Close()
+	}
+}
+
+func (c *Client) dial() (*conn, error) {
+	nc, err := net.DialTimeout("tcp", c.opts.Addr, c.opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{
+		Conn: nc,
+		r:    bufio.NewReader(nc),
+		w:    bufio.NewWriter(nc),
+	}
+
+	if c.opts.Password != "" {
+		err = cn.call(c.opts.Timeout, "AUTH", c.opts.Password)
+		if err != nil {
+			_ = cn.Close()
+
+			return nil, err
+		}
+	}
+
+	if c.opts.DB != 0 {
+		err = cn.call(c.opts.Timeout, "SELECT", strconv.Itoa(c.opts.DB))
+		if err != nil {
+			_ = cn.Close()
+
+			return nil, err
+		}
+	}
+
+	return cn, nil
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func (cn *conn) do(timeout time.Duration, args ...string) (interface{}, error) {
+	err := cn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([][]byte, len(args))
+	for i, arg := range args {
+		data[i] = []byte(arg)
+	}
+
+	err = WriteCommand(cn.w, data...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadReply(cn.r)
+}
+
+// call Sends the command which is expected to succeed.
+func (cn *conn) call(timeout time.Duration, args ...string) error {
+	reply, err := cn.do(timeout, args...)
+	if err != nil {
+		return err
+	}
+
+	if e, ok := reply.(Error); ok {
+		return e
+	}
+
+	return nil
+}
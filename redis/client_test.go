@@ -0,0 +1,48 @@
+package redis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api/redis/redistest"
+)
+
+func TestClientSetTTL(t *testing.T) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("unable to start the fake Redis server: %v", err)
+	}
+	defer server.Close()
+
+	client := server.Client()
+	defer client.Close()
+
+	cases := []struct {
+		ttl  time.Duration
+		px   int64
+		sent bool
+	}{
+		{ttl: 0},
+		{ttl: time.Microsecond, px: 1, sent: true},
+		{ttl: 1500 * time.Microsecond, px: 2, sent: true},
+		{ttl: time.Second, px: 1000, sent: true},
+	}
+
+	for _, tc := range cases {
+		if err := client.Set("key", []byte("value"), tc.ttl); err != nil {
+			t.Errorf("unable to set the key with ttl %s: %v", tc.ttl, err)
+			continue
+		}
+
+		// the fake server keeps the received PX, so the ttl is checked without waiting for the expiration
+		ttl, sent := server.TTL("key")
+		if sent != tc.sent || ttl != time.Duration(tc.px)*time.Millisecond {
+			t.Errorf("unexpected ttl received for %s: got %s (%v), want PX %d", tc.ttl, ttl, sent, tc.px)
+		}
+
+		value, err := client.Get("key")
+		if err != nil || string(value) != "value" {
+			t.Errorf("unexpected value: got %q (%v), want %q", value, err, "value")
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s-larionov/telegram-api/redis"
+)
+
+var errUnsupportedReply = errors.New("unsupported reply type")
+
+// Server An in-process fake of a Redis server. It supports PING, AUTH, SELECT, GET, SET (with EX and PX), DEL, EXISTS,
+// KEYS, FLUSHALL and QUIT, which is enough for the session storage.
+type Server struct {
+	password string
+	listener net.Listener
+	lock     sync.Mutex
+	values   map[string][]byte
+	expires  map[string]time.Time
+	ttls     map[string]time.Duration
+	offset   time.Duration
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer Starts the server on a random local port.
+func NewServer() (*Server, error) {
+	return NewServerWithPassword("")
+}
+
+// NewServerWithPassword Starts the server requiring the password by AUTH.
+func NewServerWithPassword(password string) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		password: password,
+		listener: listener,
+		values:   make(map[string][]byte),
+		expires:  make(map[string]time.Time),
+		ttls:     make(map[string]time.Duration),
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.accept()
+
+	return s, nil
+}
+
+// Addr Returns the address the server is listening on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Client Returns the client connected to the server.
+func (s *Server) Client() *redis.Client {
+	return redis.NewClient(redis.Options{
+		Addr:     s.Addr(),
+		Password: s.password,
+	})
+}
+
+// Keys Returns the sorted keys which haven't expired.
+func (s *Server) Keys() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		if s.alive(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Get Returns the value of the key.
+func (s *Server) Get(key string) ([]byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.alive(key) {
+		return nil, false
+	}
+
+	value, ok := s.values[key]
+
+	return value, ok
+}
+
+// TTL Returns the time to live the key was set with. Returns false if the key has no expiration or doesn't exist.
+func (s *Server) TTL(key string) (time.Duration, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.alive(key) {
+		return 0, false
+	}
+
+	ttl, ok := s.ttls[key]
+
+	return ttl, ok
+}
+
+// FastForward Moves the clock of the server forward, so that keys expire without waiting.
+func (s *Server) FastForward(d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.offset += d
+}
+
+// Close Stops the server and closes client connections.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+
+	s.lock.Lock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.lock.Lock()
+		s.conns[c] = struct{}{}
+		s.lock.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(c)
+	}
+}
+
+func (s *Server) serve(c net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, c)
+		s.lock.Unlock()
+
+		_ = c.Close()
+	}()
+
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	authenticated := s.password == ""
+
+	for {
+		request, err := redis.ReadReply(r)
+		if err != nil {
+			return
+		}
+
+		args, ok := commandArgs(request)
+		if !ok {
+			_ = writeReply(w, redis.Error("ERR Protocol error"))
+			return
+		}
+
+		command := strings.ToUpper(args[0])
+
+		var reply interface{}
+		switch {
+		case command == "QUIT":
+			_ = writeReply(w, "OK")
+			return
+		case command == "AUTH":
+			authenticated = len(args) == 2 && args[1] == s.password
+			reply = "OK"
+			if !authenticated {
+				reply = redis.Error("WRONGPASS invalid password")
+			}
+		case !authenticated:
+			reply = redis.Error("NOAUTH Authentication required.")
+		default:
+			reply = s.exec(command, args[1:])
+		}
+
+		err = writeReply(w, reply)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(command string, args []string) interface{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch command {
+	case "PING":
+		return "PONG"
+	case "SELECT":
+		return "OK"
+	case "GET":
+		if len(args) != 1 {
+			return wrongArgs(command)
+		}
+
+		if !s.alive(args[0]) {
+			return nil
+		}
+
+		value, ok := s.values[args[0]]
+		if !ok {
+			return nil
+		}
+
+		return value
+	case "SET":
+		return s.set(args)
+	case "DEL", "EXISTS":
+		var n int64
+		for _, key := range args {
+			if _, ok := s.values[key]; !ok || !s.alive(key) {
+				continue
+			}
+
+			n++
+			if command == "DEL" {
+				delete(s.values, key)
+				delete(s.expires, key)
+				delete(s.ttls, key)
+			}
+		}
+
+		return n
+	case "KEYS":
+		keys := make([]interface{}, 0, len(s.values))
+		for key := range s.values {
+			if s.alive(key) {
+				keys = append(keys, []byte(key))
+			}
+		}
+
+		return keys
+	case "FLUSHALL", "FLUSHDB":
+		s.values = make(map[string][]byte)
+		s.expires = make(map[string]time.Time)
+		s.ttls = make(map[string]time.Duration)
+
+		return "OK"
+	default:
+	}
+
+	return redis.Error("ERR unknown command '" + command + "'")
+}
+
+func (s *Server) set(args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArgs("SET")
+	}
+
+	var ttl time.Duration
+	for i := 2; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		if option != "EX" && option != "PX" || i+1 >= len(args) {
+			return redis.Error("ERR syntax error")
+		}
+
+		n, err := strconv.ParseInt(args[i+1], 10, 64)
+		if err != nil || n <= 0 {
+			return redis.Error("ERR invalid expire time in 'set' command")
+		}
+
+		ttl = time.Duration(n) * time.Millisecond
+		if option == "EX" {
+			ttl = time.Duration(n) * time.Second
+		}
+		i++
+	}
+
+	s.values[args[0]] = []byte(args[1])
+	delete(s.expires, args[0])
+	delete(s.ttls, args[0])
+	if ttl > 0 {
+		s.expires[args[0]] = s.now().Add(ttl)
+		s.ttls[args[0]] = ttl
+	}
+
+	return "OK"
+}
+
+// alive Reports whether the key hasn't expired and removes the expired one.
+func (s *Server) alive(key string) bool {
+	expires, ok := s.expires[key]
+	if !ok || s.now().Before(expires) {
+		return true
+	}
+
+	delete(s.values, key)
+	delete(s.expires, key)
+	delete(s.ttls, key)
+
+	return false
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func commandArgs(request interface{}) ([]string, bool) {
+	values, ok := request.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, false
+	}
+
+	args := make([]string, len(values))
+	for i, value := range values {
+		arg, ok := value.([]byte)
+		if !ok {
+			return nil, false
+		}
+
+		args[i] = string(arg)
+	}
+
+	return args, true
+}
+
+func wrongArgs(command string) redis.Error {
+	return redis.Error("ERR wrong number of arguments for '" + strings.ToLower(command) + "' command")
+}
+
+func writeReply(w *bufio.Writer, reply interface{}) error {
+	err := encodeReply(w, reply)
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func encodeReply(w *bufio.Writer, reply interface{}) error {
+	var err error
+
+	switch v := reply.(type) {
+	case nil:
+		_, err = w.WriteString("$-1\r\n")
+	case string:
+		_, err = w.WriteString("+" + v + "\r\n")
+	case redis.Error:
+		_, err = w.WriteString("-" + string(v) + "\r\n")
+	case int64:
+		_, err = w.WriteString(":" + strconv.FormatInt(v, 10) + "\r\n")
+	case []byte:
+		_, err = w.WriteString("$" + strconv.Itoa(len(v)) + "\r\n" + string(v) + "\r\n")
+	case []interface{}:
+		_, err = w.WriteString("*" + strconv.Itoa(len(v)) + "\r\n")
+		for _, item := range v {
+			if err != nil {
+				break
+			}
+
+			err = encodeReply(w, item)
+		}
+	default:
+		err = errUnsupportedReply
+	}
+
+	return err
+}
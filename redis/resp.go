@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var ErrProtocol = errors.New("redis protocol error")
+
+// Error An error reply of the server.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// WriteCommand Writes the command as a RESP array of bulk strings.
+func WriteCommand(w *bufio.Writer, args ...[]byte) error {
+	_, err := fmt.Fprintf(w, "*%d\r\n", len(args))
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		_, err = fmt.Fprintf(w, "$%d\r\n", len(arg))
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.WriteString("\r\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// ReadReply Reads a RESP value. Simple strings are returned as string, errors as Error, integers as int64,
+// bulk strings as []byte, arrays as []interface{}. Null bulk strings and arrays are returned as nil.
+func ReadReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, ErrProtocol
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, ErrProtocol
+		}
+
+		if n < 0 {
+			return nil, nil
+		}
+
+		data := make([]byte, n+2)
+		_, err = io.ReadFull(r, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, ErrProtocol
+		}
+
+		if n < 0 {
+			return nil, nil
+		}
+
+		values := make([]interface{}, n)
+		for i := range values {
+			values[i], err = ReadReply(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	default:
+	}
+
+	return nil, ErrProtocol
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", ErrProtocol
+	}
+
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,109 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxDownloadFileSize Bots can download files of up to 20MB in size.
+const MaxDownloadFileSize = 20 << 20
+
+var ErrFileTooLarge = fmt.Errorf("file is too large: bots can download files of up to %d bytes", MaxDownloadFileSize)
+
+var ErrOutsideLocalRoot = errors.New("file is outside the root directory of the local mode")
+
+// IsLocalFile Reports whether the file by its file_path received from getFile is read from the local filesystem.
+// It is true only in the local mode for absolute paths under its root directory.
+func (r *Requester) IsLocalFile(filePath string) bool {
+	return r.localRoot != "" && filepath.IsAbs(filePath) && isWithin(r.localRoot, filepath.Clean(filePath))
+}
+
+// FileURL Returns the link to download the file by its file_path received from getFile. Absolute paths outside
+// the root directory of the local mode are downloaded over HTTP relative to the file endpoint of the server.
+func (r *Requester) FileURL(filePath string) string {
+	if r.IsLocalFile(filePath) {
+		return (&url.URL{Scheme: "file", Path: filePath}).String()
+	}
+
+	return fmt.Sprintf("%s/file/bot%s/%s", r.baseURL, r.token, strings.TrimLeft(filePath, "/"))
+}
+
+// Download Streams the file by its file_path into w. Returns the number of written bytes. In the local mode files
+// under its root directory are read from the filesystem without the size limit.
+func (r *Requester) Download(ctx context.Context, filePath string, w io.Writer) (int64, error) {
+	if filePath == "" {
+		return 0, errors.New("file path is empty")
+	}
+
+	if r.IsLocalFile(filePath) {
+		return r.copyLocalFile(filePath, w)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", r.FileURL(filePath), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, &APIError{ErrorCode: resp.StatusCode, Description: http.StatusText(resp.StatusCode)}
+	}
+
+	if resp.ContentLength > MaxDownloadFileSize {
+		return 0, ErrFileTooLarge
+	}
+
+	n, err := io.Copy(w, io.LimitReader(resp.Body, MaxDownloadFileSize+1))
+	if err != nil {
+		return n, err
+	}
+
+	if n > MaxDownloadFileSize {
+		return n, ErrFileTooLarge
+	}
+
+	return n, nil
+}
+
+// copyLocalFile Copies the file into w. Symbolic links are resolved first, so they can't point outside the root.
+func (r *Requester) copyLocalFile(filePath string, w io.Writer) (int64, error) {
+	root, err := filepath.EvalSymlinks(r.localRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	filePath, err = filepath.EvalSymlinks(filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	if !isWithin(root, filePath) {
+		return 0, ErrOutsideLocalRoot
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return io.Copy(w, f)
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,103 @@
+package request_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/s-larionov/telegram-api/request"
+	"github.com/s-larionov/telegram-api/telegramtest"
+)
+
+func TestRequesterFileURL(t *testing.T) {
+	root, err := ioutil.TempDir("", "bot-api")
+	if err != nil {
+		t.Fatalf("unable to create the root directory: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	const base = "http://localhost:8081"
+
+	r := request.NewRequester("123:token", request.WithBaseURL(base+"/"), request.WithLocalMode(root))
+
+	cases := []struct {
+		name     string
+		filePath string
+		want     string
+	}{
+		{
+			name:     "relative",
+			filePath: "documents/file_1.txt",
+			want:     base + "/file/bot123:token/documents/file_1.txt",
+		},
+		{
+			name:     "inside the root",
+			filePath: filepath.Join(root, "documents", "file_1.txt"),
+			want:     "file://" + filepath.ToSlash(filepath.Join(root, "documents", "file_1.txt")),
+		},
+		{
+			name:     "outside the root",
+			filePath: "/var/lib/other/file_1.txt",
+			want:     base + "/file/bot123:token/var/lib/other/file_1.txt",
+		},
+		{
+			name:     "sibling of the root",
+			filePath: root + "-other/file_1.txt",
+			want:     base + "/file/bot123:token/" + root[1:] + "-other/file_1.txt",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := r.FileURL(tc.filePath); got != tc.want {
+				t.Errorf("unexpected url: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRequesterDownloadInLocalMode(t *testing.T) {
+	root, err := ioutil.TempDir("", "bot-api")
+	if err != nil {
+		t.Fatalf("unable to create the root directory: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	local := filepath.Join(root, "file_1.txt")
+	if err := ioutil.WriteFile(local, []byte("local"), 0600); err != nil {
+		t.Fatalf("unable to write the file: %v", err)
+	}
+
+	server := telegramtest.NewServer()
+	defer server.Close()
+
+	server.AddFile("var/lib/other/file_1.txt", []byte("remote"))
+
+	r := request.NewRequesterWithClient(server.Token, server.Client(),
+		request.WithBaseURL(server.URL), request.WithLocalMode(root))
+
+	cases := []struct {
+		name     string
+		filePath string
+		want     string
+	}{
+		{name: "inside the root", filePath: local, want: "local"},
+		{name: "outside the root", filePath: "/var/lib/other/file_1.txt", want: "remote"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if _, err := r.Download(context.Background(), tc.filePath, &b); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if b.String() != tc.want {
+				t.Errorf("unexpected content: got %q, want %q", b.String(), tc.want)
+			}
+		})
+	}
+}
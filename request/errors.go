@@ -0,0 +1,128 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+const messageNotModifiedDescription = "message is not modified"
+
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrFloodWait          = errors.New("too many requests")
+	ErrChatMigrated       = errors.New("group chat was migrated to a supergroup")
+	ErrMessageNotModified = errors.New("message is not modified")
+)
+
+// APIError An unsuccessful response of the Bot API. Can be matched with errors.Is against the Err* sentinels
+// of this package, e.g. errors.Is(err, request.ErrForbidden).
+type APIError struct {
+	// Code of the error, mostly the same as HTTP status code
+	ErrorCode int
+
+	// Human-readable description of the error
+	Description string
+
+	// Information about why the request was unsuccessful
+	Parameters models.ResponseParameters
+}
+
+func newAPIError(response *Response) *APIError {
+	err := &APIError{
+		ErrorCode:   response.ErrorCode,
+		Description: response.Description,
+	}
+
+	if response.Parameters != nil {
+		err.Parameters = *response.Parameters
+	}
+
+	return err
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("[%d] %s", e.ErrorCode, e.Description)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.ErrorCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.ErrorCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.ErrorCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.ErrorCode == http.StatusNotFound
+	case ErrConflict:
+		return e.ErrorCode == http.StatusConflict
+	case ErrFloodWait:
+		return e.ErrorCode == http.StatusTooManyRequests
+	case ErrChatMigrated:
+		return e.Parameters.MigrateToChatID != 0
+	case ErrMessageNotModified:
+		return e.ErrorCode == http.StatusBadRequest &&
+			strings.Contains(strings.ToLower(e.Description), messageNotModifiedDescription)
+	default:
+	}
+
+	return false
+}
+
+// RetryAfter Returns how long to wait before the request can be repeated in case of exceeding flood control.
+func (e *APIError) RetryAfter() time.Duration {
+	return time.Duration(e.Parameters.RetryAfter) * time.Second
+}
+
+// IsFloodWait Reports whether err is caused by exceeding flood control. The wait time is available
+// via RetryAfter of the *APIError.
+func IsFloodWait(err error) bool {
+	return errors.Is(err, ErrFloodWait)
+}
+
+// IsForbidden Reports whether the bot has no rights to perform the request, e.g. it was blocked by the user
+// or kicked from the chat.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsChatMigrated Reports whether the group was migrated to a supergroup. The new identifier is available
+// via MigrateToChatID.
+func IsChatMigrated(err error) bool {
+	return errors.Is(err, ErrChatMigrated)
+}
+
+// IsMessageNotModified Reports whether the edit request was rejected because the new content
+// is the same as the current one.
+func IsMessageNotModified(err error) bool {
+	return errors.Is(err, ErrMessageNotModified)
+}
+
+// RetryAfter Returns the flood wait time carried by err, if any.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Parameters.RetryAfter == 0 {
+		return 0, false
+	}
+
+	return apiErr.RetryAfter(), true
+}
+
+// MigrateToChatID Returns the identifier of the supergroup the chat was migrated to, if err carries it.
+func MigrateToChatID(err error) (int64, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Parameters.MigrateToChatID == 0 {
+		return 0, false
+	}
+
+	return apiErr.Parameters.MigrateToChatID, true
+}
@@ -0,0 +1,266 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGlobalInterval  = time.Second / 30
+	defaultChatInterval    = time.Second
+	defaultGroupInterval   = time.Minute / 20
+	limiterCleanupInterval = time.Minute
+)
+
+// Limiter Throttles outgoing requests. Wait blocks until the request to the method for the chat can be sent.
+// chatID is empty for requests without a target chat.
+type Limiter interface {
+	Wait(ctx context.Context, method, chatID string) error
+}
+
+// LimiterStats Wait-time metrics of the SendLimiter.
+type LimiterStats struct {
+	// Number of sent requests. Requests canceled while waiting aren't counted.
+	Requests int64
+
+	// Number of requests which had to wait for a free slot
+	Delayed int64
+
+	// Total time spent in waiting
+	TotalWait time.Duration
+
+	// The longest wait
+	MaxWait time.Duration
+}
+
+// SendLimiter Spreads sending requests (sendMessage, forwardMessage, etc.) according to the Telegram limits:
+// about 30 messages per second overall, 1 message per second to the same chat and 20 messages per minute
+// to the same group. Requests are queued in the order of arrival instead of failing: requests to the same chat
+// are sent one by one in that order, and a canceled request leaves the queue without taking a slot.
+type SendLimiter struct {
+	// Minimal interval between any two messages
+	GlobalInterval time.Duration
+
+	// Minimal interval between two messages to the same private chat
+	ChatInterval time.Duration
+
+	// Minimal interval between two messages to the same group or channel
+	GroupInterval time.Duration
+
+	lock        sync.Mutex
+	next        time.Time
+	chats       map[string]*chatQueue
+	lastCleanup time.Time
+	stats       LimiterStats
+}
+
+// chatQueue Requests to one chat waiting for the slot. Only the first of them waits for the slot, the others wait
+// for their turn, which comes when the previous request is sent or canceled.
+type chatQueue struct {
+	next    time.Time
+	waiters []chan struct{}
+}
+
+func NewSendLimiter() *SendLimiter {
+	return &SendLimiter{
+		GlobalInterval: defaultGlobalInterval,
+		ChatInterval:   defaultChatInterval,
+		GroupInterval:  defaultGroupInterval,
+	}
+}
+
+func (l *SendLimiter) Wait(ctx context.Context, method, chatID string) error {
+	if !isSendMethod(method) {
+		return nil
+	}
+
+	started := time.Now()
+	turn := l.enqueue(chatID)
+
+	var waitingSince time.Time
+
+	select {
+	case <-turn:
+	default:
+		waitingSince = started
+
+		select {
+		case <-turn:
+		case <-ctx.Done():
+			l.leave(chatID, turn)
+
+			return ctx.Err()
+		}
+	}
+
+	for {
+		delay := l.reserve(chatID, waitingSince)
+		if delay <= 0 {
+			return nil
+		}
+
+		if waitingSince.IsZero() {
+			waitingSince = started
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.leave(chatID, turn)
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Stats Returns a snapshot of the wait-time metrics.
+func (l *SendLimiter) Stats() LimiterStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.stats
+}
+
+// enqueue Adds the request to the queue of the chat. The returned channel is closed when the request is the first
+// in the queue.
+func (l *SendLimiter) enqueue(chatID string) chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.chats == nil {
+		l.chats = make(map[string]*chatQueue)
+	}
+
+	q, ok := l.chats[chatID]
+	if !ok {
+		q = &chatQueue{}
+		l.chats[chatID] = q
+	}
+
+	turn := make(chan struct{})
+	if len(q.waiters) == 0 {
+		close(turn)
+	}
+	q.waiters = append(q.waiters, turn)
+
+	return turn
+}
+
+// leave Removes the canceled request from the queue of the chat and passes the turn to the next one.
+func (l *SendLimiter) leave(chatID string, turn chan struct{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	q := l.chats[chatID]
+	for i, waiter := range q.waiters {
+		if waiter != turn {
+			continue
+		}
+
+		q.waiters = append(q.waiters[:i:i], q.waiters[i+1:]...)
+		if i == 0 && len(q.waiters) > 0 {
+			close(q.waiters[0])
+		}
+
+		return
+	}
+}
+
+// reserve Takes the slot for the first request of the chat if it is free and returns zero, otherwise returns the time
+// until the slot is released. Slots are taken only by requests which are sent right away, so canceled requests
+// don't delay the others. waitingSince is the start of the wait, or zero if the request hasn't waited yet.
+func (l *SendLimiter) reserve(chatID string, waitingSince time.Time) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	q := l.chats[chatID]
+
+	at := now
+	if l.next.After(at) {
+		at = l.next
+	}
+
+	if q.next.After(at) {
+		at = q.next
+	}
+
+	if delay := at.Sub(now); delay > 0 {
+		return delay
+	}
+
+	if chatID != "" {
+		interval := l.ChatInterval
+		if isGroupChatID(chatID) {
+			interval = l.GroupInterval
+		}
+		q.next = now.Add(interval)
+	}
+
+	l.next = now.Add(l.GlobalInterval)
+
+	q.waiters = q.waiters[1:]
+	if len(q.waiters) > 0 {
+		close(q.waiters[0])
+	}
+
+	l.stats.Requests++
+	if !waitingSince.IsZero() {
+		wait := now.Sub(waitingSince)
+
+		l.stats.Delayed++
+		l.stats.TotalWait += wait
+		if wait > l.stats.MaxWait {
+			l.stats.MaxWait = wait
+		}
+	}
+
+	l.cleanup(now)
+
+	return 0
+}
+
+func (l *SendLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < limiterCleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+
+	for chatID, q := range l.chats {
+		if len(q.waiters) == 0 && q.next.Before(now) {
+			delete(l.chats, chatID)
+		}
+	}
+}
+
+func isSendMethod(method string) bool {
+	return strings.HasPrefix(method, "send") || method == "forwardMessage"
+}
+
+// isGroupChatID Groups, supergroups and channels have negative identifiers or are addressed by @username.
+func isGroupChatID(chatID string) bool {
+	return strings.HasPrefix(chatID, "-") || strings.HasPrefix(chatID, "@")
+}
+
+func chatIDFromJSON(body []byte) string {
+	var request struct {
+		ChatID json.RawMessage `json:"chat_id"`
+	}
+
+	if err := json.Unmarshal(body, &request); err != nil || len(request.ChatID) == 0 {
+		return ""
+	}
+
+	var chatID string
+	if err := json.Unmarshal(request.ChatID, &chatID); err == nil {
+		return chatID
+	}
+
+	return string(request.ChatID)
+}
@@ -0,0 +1,166 @@
+package request_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api/request"
+)
+
+func newLimiter(chatInterval time.Duration) *request.SendLimiter {
+	l := request.NewSendLimiter()
+	l.GlobalInterval = 0
+	l.ChatInterval = chatInterval
+	l.GroupInterval = chatInterval
+
+	return l
+}
+
+func TestSendLimiterKeepsOrder(t *testing.T) {
+	l := newLimiter(5 * time.Millisecond)
+
+	var (
+		lock  sync.Mutex
+		order []int
+		wg    sync.WaitGroup
+	)
+
+	const n = 20
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			if err := l.Wait(context.Background(), "sendMessage", "42"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			lock.Lock()
+			order = append(order, i)
+			lock.Unlock()
+		}(i)
+
+		// lets the request join the queue before the next one
+		time.Sleep(time.Millisecond)
+	}
+
+	wg.Wait()
+
+	want := make([]int, n)
+	for i := range want {
+		want[i] = i
+	}
+
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("requests are sent out of order: %v", order)
+	}
+}
+
+func TestSendLimiterSpacesRequests(t *testing.T) {
+	l := newLimiter(30 * time.Millisecond)
+
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), "sendMessage", "-100"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(started); elapsed < 60*time.Millisecond {
+		t.Errorf("requests to the same chat aren't spaced: 3 requests in %s", elapsed)
+	}
+
+	// other chats and other methods don't wait for the chat
+	started = time.Now()
+	if err := l.Wait(context.Background(), "sendMessage", "7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := l.Wait(context.Background(), "getMe", "-100"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(started); elapsed > 20*time.Millisecond {
+		t.Errorf("unrelated requests are delayed by %s", elapsed)
+	}
+}
+
+func TestSendLimiterCancel(t *testing.T) {
+	l := newLimiter(100 * time.Millisecond)
+
+	if err := l.Wait(context.Background(), "sendMessage", "42"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	started := time.Now()
+
+	// the first canceled request waits for the slot, the second one for its turn
+	canceled := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		go func() {
+			canceled <- l.Wait(ctx, "sendMessage", "42")
+		}()
+
+		time.Sleep(time.Millisecond)
+	}
+
+	sent := make(chan time.Duration, 1)
+	go func() {
+		if err := l.Wait(context.Background(), "sendMessage", "42"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		sent <- time.Since(started)
+	}()
+
+	for i := 0; i < 2; i++ {
+		if err := <-canceled; err != context.DeadlineExceeded {
+			t.Errorf("unexpected error of the canceled request: got %v, want %v", err, context.DeadlineExceeded)
+		}
+	}
+
+	// canceled requests don't take slots, so the request is sent right after the first one
+	if elapsed := <-sent; elapsed > 180*time.Millisecond {
+		t.Errorf("the request is delayed by canceled ones: sent in %s", elapsed)
+	}
+}
+
+func TestSendLimiterStats(t *testing.T) {
+	l := newLimiter(20 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), "sendMessage", "42"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx, "sendMessage", "42"); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	if err := l.Wait(context.Background(), "getUpdates", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := l.Stats()
+	if stats.Requests != 3 || stats.Delayed != 2 {
+		t.Errorf("unexpected number of requests: got %d sent and %d delayed, want 3 and 2",
+			stats.Requests, stats.Delayed)
+	}
+
+	if stats.MaxWait < 10*time.Millisecond || stats.MaxWait > stats.TotalWait {
+		t.Errorf("unexpected wait time: max %s, total %s", stats.MaxWait, stats.TotalWait)
+	}
+
+	if stats.TotalWait < 30*time.Millisecond {
+		t.Errorf("unexpected total wait time: %s", stats.TotalWait)
+	}
+}
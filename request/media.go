@@ -0,0 +1,83 @@
+package request
+
+import (
+	"fmt"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+// attachments Collects files of InputMedia objects which have to be uploaded and replaces them
+// with “attach://<file_attach_name>” references.
+type attachments struct {
+	files map[string]*models.InputFile
+	next  int
+}
+
+func newAttachments(files map[string]*models.InputFile) *attachments {
+	return &attachments{files: files}
+}
+
+// attachMedia Rewrites the InputMedia object or the list of them. Other values are returned as is.
+func (a *attachments) attachMedia(value interface{}) interface{} {
+	switch v := value.(type) {
+	case models.InputMediaInterface:
+		return a.attach(v)
+	case []models.InputMediaInterface:
+		media := make([]models.InputMediaInterface, len(v))
+		for i, m := range v {
+			media[i] = a.attach(m)
+		}
+
+		return media
+	default:
+	}
+
+	return value
+}
+
+func (a *attachments) attach(media models.InputMediaInterface) models.InputMediaInterface {
+	if media == nil {
+		return nil
+	}
+
+	return media.WithFiles(a.attachFile(media.GetMedia()), a.attachFile(media.GetThumb()))
+}
+
+func (a *attachments) attachFile(file *models.InputFile) *models.InputFile {
+	if file == nil || !file.IsUpload() {
+		return file
+	}
+
+	name := fmt.Sprintf("file%d", a.next)
+	a.next++
+
+	a.files[name] = file
+
+	return models.FromAttachment(name)
+}
+
+func hasMediaUploads(value interface{}) bool {
+	var media []models.InputMediaInterface
+
+	switch v := value.(type) {
+	case models.InputMediaInterface:
+		media = append(media, v)
+	case []models.InputMediaInterface:
+		media = v
+	default:
+	}
+
+	for _, m := range media {
+		if m == nil {
+			continue
+		}
+
+		for _, file := range []*models.InputFile{m.GetMedia(), m.GetThumb()} {
+			if file != nil && file.IsUpload() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package request
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Option Configures optional behaviour of the Requester.
+type Option func(r *Requester)
+
+// WithRetryPolicy Sets the policy used to retry failed requests. By default requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *Requester) {
+		r.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter Throttles requests with the limiter, e.g. NewSendLimiter(). By default requests are not throttled.
+func WithRateLimiter(limiter Limiter) Option {
+	return func(r *Requester) {
+		r.limiter = limiter
+	}
+}
+
+// WithBaseURL Sends requests to the Bot API server at baseURL instead of https://api.telegram.org,
+// e.g. to a self-hosted server or a test double.
+func WithBaseURL(baseURL string) Option {
+	return func(r *Requester) {
+		r.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithLocalMode Reads files stored by a self-hosted Bot API server running with --local from the filesystem instead
+// of downloading them. Only files under root, the working directory of the server, are read locally; other paths
+// are downloaded over HTTP. Use it only if the server runs on the same host.
+func WithLocalMode(root string) Option {
+	return func(r *Requester) {
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+
+		r.localRoot = filepath.Clean(root)
+	}
+}
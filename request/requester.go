@@ -2,13 +2,13 @@ package request
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"reflect"
 	"strings"
 
@@ -17,9 +17,7 @@ import (
 	"github.com/s-larionov/telegram-api/models"
 )
 
-const apiURL = "https://api.telegram.org"
-
-var fileType = reflect.TypeOf(models.InputFile(""))
+const DefaultBaseURL = "https://api.telegram.org"
 
 type Response struct {
 	Ok          bool                       `json:"ok"`
@@ -30,23 +28,48 @@ type Response struct {
 }
 
 type Requester struct {
-	token  string
-	client *http.Client
+	token       string
+	baseURL     string
+	client      *http.Client
+	retryPolicy RetryPolicy
+	limiter     Limiter
+	localRoot   string
+}
+
+func NewRequester(token string, opts ...Option) *Requester {
+	return NewRequesterWithClient(token, http.DefaultClient, opts...)
+}
+
+func NewRequesterWithClient(token string, client *http.Client, opts ...Option) *Requester {
+	r := &Requester{
+		token:       token,
+		baseURL:     DefaultBaseURL,
+		client:      client,
+		retryPolicy: NoRetry{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
-func NewRequester(token string) *Requester {
-	return NewRequesterWithClient(token, http.DefaultClient)
+func (r *Requester) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", r.baseURL, r.token, method)
 }
 
-func NewRequesterWithClient(token string, client *http.Client) *Requester {
-	return &Requester{
-		token:  token,
-		client: client,
+// Request Sends the request as JSON, or using multipart/form-data if it contains files to be uploaded.
+func (r *Requester) Request(ctx context.Context, method string, request interface{}) (json.RawMessage, error) {
+	if hasUploads(request) {
+		return r.MultipartRequest(ctx, method, request)
 	}
+
+	return r.JSONRequest(ctx, method, request)
 }
 
-func (r *Requester) JSONRequest(method string, request interface{}) (json.RawMessage, error) {
-	url := fmt.Sprintf("%s/bot%s/%s", apiURL, r.token, method)
+func (r *Requester) JSONRequest(ctx context.Context, method string, request interface{}) (json.RawMessage, error) {
+	url := r.methodURL(method)
 
 	body, err := json.Marshal(request)
 	if err != nil {
@@ -55,7 +78,7 @@ func (r *Requester) JSONRequest(method string, request interface{}) (json.RawMes
 
 	log.WithField("body", string(body)).Trace("request")
 
-	response, err := r.jsonRequest(url, body)
+	response, err := r.jsonRequest(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -71,25 +94,25 @@ func (r *Requester) JSONRequest(method string, request interface{}) (json.RawMes
 	return response.Result, nil
 }
 
-func (r *Requester) jsonRequest(url string, body []byte) (*Response, error) {
-	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+func (r *Requester) jsonRequest(ctx context.Context, method, url string, body []byte) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	return r.execute(req)
+	return r.execute(method, chatIDFromJSON(body), req)
 }
 
-func (r *Requester) MultipartRequest(method string, request interface{}) (json.RawMessage, error) {
-	url := fmt.Sprintf("%s/bot%s/%s", apiURL, r.token, method)
+func (r *Requester) MultipartRequest(ctx context.Context, method string, request interface{}) (json.RawMessage, error) {
+	url := r.methodURL(method)
 
 	params, files, err := r.prepareMultipartRequestData(request)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := r.multipartRequest(url, params, files)
+	response, err := r.multipartRequest(ctx, method, url, params, files)
 	if err != nil {
 		return nil, err
 	}
@@ -107,52 +130,35 @@ func (r *Requester) MultipartRequest(method string, request interface{}) (json.R
 
 func (r Requester) prepareMultipartRequestData(
 	request interface{},
-) (params map[string]string, files map[string]string, err error) {
+) (params map[string]string, files map[string]*models.InputFile, err error) {
 	params = make(map[string]string)
-	files = make(map[string]string)
-
-	v := reflect.ValueOf(request)
-	t := v.Type()
-	if t.Kind() == reflect.Ptr {
-		v = v.Elem()
-		t = v.Type()
-	}
-
-	// FIXME: Support t.Kind() == reflect.Map
+	files = make(map[string]*models.InputFile)
 
-	if t.Kind() != reflect.Struct {
-		return nil, nil, errors.New("incorrect type of request: must be struct")
+	fields, err := requestFields(request)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	for i := 0; i < v.NumField(); i++ {
-		fv := v.Field(i)
-		ft := fv.Type()
-
-		name, omitempty := parseJSONTag(t.Field(i))
-		if name == "" {
-			name = ft.Name()
-		}
+	attachments := newAttachments(files)
 
-		isNil := ft.Kind() == reflect.Ptr && fv.IsNil()
-		isZero := ft.Kind() != reflect.Ptr && fv.IsZero()
-		isEmpty := (ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array) && fv.Len() == 0
-
-		if omitempty && (isNil || isZero || isEmpty) {
-			continue
-		}
+	for name, value := range fields {
+		value = attachments.attachMedia(value)
 
-		if ft == fileType {
-			file := fv.Interface().(models.InputFile)
-			files[name] = string(file)
+		if file, ok := value.(*models.InputFile); ok {
+			if file.IsUpload() {
+				files[name] = file
+			} else {
+				params[name] = file.Ref()
+			}
 			continue
 		}
 
-		if ft.Kind() == reflect.String {
-			params[name] = fv.String()
+		if str, ok := stringValue(value); ok {
+			params[name] = str
 			continue
 		}
 
-		data, err := json.Marshal(fv.Interface())
+		data, err := json.Marshal(value)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -163,44 +169,63 @@ func (r Requester) prepareMultipartRequestData(
 	return params, files, err
 }
 
-func (r *Requester) multipartRequest(url string, params, files map[string]string) (*Response, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	for name, file := range files {
-		part, err := writer.CreateFormFile(name, file)
-		if err != nil {
-			return nil, err
+func (r *Requester) multipartRequest(
+	ctx context.Context,
+	method, url string,
+	params map[string]string,
+	files map[string]*models.InputFile,
+) (*Response, error) {
+	boundary := multipart.NewWriter(nil).Boundary()
+	upload := newUpload(boundary, params, files, progressFromContext(ctx))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, upload.body())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+
+	if upload.reusable() {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return upload.body(), nil
 		}
+	}
 
-		f, err := os.Open(file)
-		if err != nil {
-			return nil, err
+	return r.execute(method, params["chat_id"], req)
+}
+
+func (r *Requester) execute(method, chatID string, req *http.Request) (*Response, error) {
+	for attempt := 1; ; attempt++ {
+		if r.limiter != nil {
+			if err := r.limiter.Wait(req.Context(), method, chatID); err != nil {
+				return nil, err
+			}
 		}
 
-		_, err = io.Copy(part, f)
-		_ = f.Close()
-		if err != nil {
+		response, err := r.do(req)
+		if err == nil {
+			return response, nil
+		}
+
+		if req.GetBody == nil || !r.waitForRetry(req.Context(), method, attempt, err) {
 			return nil, err
 		}
-	}
 
-	for name, value := range params {
-		err := writer.WriteField(name, value)
-		if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"method":  method,
+			"attempt": attempt,
+		}).Debug("retry request")
+
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
 			return nil, err
 		}
-	}
 
-	req, err := http.NewRequest("POST", url, body)
-	if err != nil {
-		return nil, err
+		req = req.Clone(req.Context())
+		req.Body = body
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	return r.execute(req)
 }
 
-func (r *Requester) execute(req *http.Request) (*Response, error) {
+func (r *Requester) do(req *http.Request) (*Response, error) {
 	resp, err := r.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -210,16 +235,128 @@ func (r *Requester) execute(req *http.Request) (*Response, error) {
 	var response Response
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, &APIError{ErrorCode: resp.StatusCode, Description: http.StatusText(resp.StatusCode)}
+		}
+
 		return nil, err
 	}
 
 	if !response.Ok {
-		return nil, fmt.Errorf("[%d] %s", response.ErrorCode, response.Description)
+		return nil, newAPIError(&response)
 	}
 
 	return &response, nil
 }
 
+// requestFields Returns non-empty fields of the request struct or map by their JSON names.
+func requestFields(request interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	v := reflect.ValueOf(request)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	t := v.Type()
+
+	if t.Kind() == reflect.Map {
+		if t.Key().Kind() != reflect.String {
+			return nil, errors.New("incorrect type of request: map keys must be strings")
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			fields[iter.Key().String()] = iter.Value().Interface()
+		}
+
+		return fields, nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New("incorrect type of request: must be struct or map")
+	}
+
+	collectStructFields(v, fields)
+
+	return fields, nil
+}
+
+func collectStructFields(v reflect.Value, fields map[string]interface{}) {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+		ft := fv.Type()
+		sf := t.Field(i)
+
+		name, omitempty := parseJSONTag(sf)
+		if name == "-" {
+			continue
+		}
+
+		// fields of embedded structs are promoted the same way as encoding/json does
+		if sf.Anonymous && name == "" {
+			if ft.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct {
+				collectStructFields(fv, fields)
+				continue
+			}
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		isNil := (ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Interface) && fv.IsNil()
+		isZero := ft.Kind() != reflect.Ptr && fv.IsZero()
+		isEmpty := (ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array || ft.Kind() == reflect.Map) && fv.Len() == 0
+
+		if isNil || (omitempty && (isZero || isEmpty)) {
+			continue
+		}
+
+		fields[name] = fv.Interface()
+	}
+}
+
+func hasUploads(request interface{}) bool {
+	fields, err := requestFields(request)
+	if err != nil {
+		return false
+	}
+
+	for _, value := range fields {
+		if file, ok := value.(*models.InputFile); ok && file.IsUpload() {
+			return true
+		}
+
+		if hasMediaUploads(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringValue(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+
+	return v.String(), true
+}
+
 func parseJSONTag(field reflect.StructField) (string, bool) {
 	tag := field.Tag.Get("json")
 
@@ -0,0 +1,176 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinBackoff  = 500 * time.Millisecond
+	defaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// nonIdempotentMethods Prefixes of the methods that must not be repeated blindly: in case of a network or a server
+// error the request might have been executed already, e.g. the message might have been sent.
+var nonIdempotentMethods = []string{
+	"send",
+	"forwardMessage",
+	"addStickerToSet",
+	"exportChatInviteLink",
+}
+
+// RetryPolicy Decides whether a failed request should be repeated.
+type RetryPolicy interface {
+	// Delay Returns how long to wait before the next attempt and false if the request must not be repeated.
+	// attempt is the number of the failed attempt starting from 1.
+	Delay(method string, attempt int, err error) (time.Duration, bool)
+}
+
+// NoRetry A policy which never repeats requests.
+type NoRetry struct{}
+
+func (NoRetry) Delay(_ string, _ int, _ error) (time.Duration, bool) {
+	return 0, false
+}
+
+// BackoffRetryPolicy Repeats requests which exceeded flood control after retry_after seconds, and requests failed
+// because of a server or a network error after an exponential backoff with jitter.
+type BackoffRetryPolicy struct {
+	// Maximum number of attempts including the first one. Defaults to 3.
+	MaxAttempts int
+
+	// Delay before the first retry of a failed request. Doubled after each attempt. Defaults to 500ms.
+	MinBackoff time.Duration
+
+	// Upper bound of the backoff and of the flood wait. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+
+	// Repeat sending methods (sendMessage, forwardMessage, etc.) after server and network errors as well.
+	// Such a retry may produce duplicates, so it is disabled by default. Flood wait errors are always retried,
+	// because the request is rejected before processing.
+	RetryNonIdempotent bool
+}
+
+func (p BackoffRetryPolicy) Delay(method string, attempt int, err error) (time.Duration, bool) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRetryMaxAttempts
+	}
+
+	if attempt >= maxAttempts {
+		return 0, false
+	}
+
+	minBackoff := p.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultRetryMinBackoff
+	}
+
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	if retryAfter, ok := RetryAfter(err); ok {
+		if retryAfter > maxBackoff {
+			return 0, false
+		}
+
+		return retryAfter, true
+	}
+
+	if !isTransient(err) {
+		return 0, false
+	}
+
+	if !p.RetryNonIdempotent && !IsIdempotent(method) {
+		return 0, false
+	}
+
+	backoff := minBackoff << uint(attempt-1)
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	// full jitter in the [backoff/2, backoff) range
+	half := int64(backoff / 2)
+	if half > 0 {
+		backoff = time.Duration(half + rand.Int63n(half)) //nolint:gosec
+	}
+
+	return backoff, true
+}
+
+// IsIdempotent Reports whether a request to the method can be repeated without side effects.
+func IsIdempotent(method string) bool {
+	for _, prefix := range nonIdempotentMethods {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isTransient Reports whether the error may disappear on the next attempt: a server error, a network error
+// or a connection closed in the middle of the response. Errors of preparing the request, e.g. a missing file
+// to upload, and errors of decoding a successful response are permanent.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode >= http.StatusInternalServerError
+	}
+
+	// the client wraps all errors including the ones of reading the request body, so only the cause matters
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+		if errors.Is(err, io.EOF) {
+			return true
+		}
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	// syscall.Errno implements net.Error too, so errors of opening files are told apart by their types
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (r *Requester) waitForRetry(ctx context.Context, method string, attempt int, err error) bool {
+	delay, ok := r.retryPolicy.Delay(method, attempt, err)
+	if !ok {
+		return false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
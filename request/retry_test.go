@@ -0,0 +1,341 @@
+package request_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/request"
+	"github.com/s-larionov/telegram-api/telegramtest"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func apiError(code int, retryAfter int) error {
+	return &request.APIError{
+		ErrorCode:   code,
+		Description: http.StatusText(code),
+		Parameters:  models.ResponseParameters{RetryAfter: retryAfter},
+	}
+}
+
+func urlError(err error) error {
+	return &url.Error{Op: "Post", URL: "https://api.telegram.org/bot/getMe", Err: err}
+}
+
+func TestBackoffRetryPolicyDelay(t *testing.T) {
+	policy := request.BackoffRetryPolicy{MaxAttempts: 5, MinBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+	cases := []struct {
+		name    string
+		policy  request.BackoffRetryPolicy
+		method  string
+		attempt int
+		err     error
+		retry   bool
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:    "flood wait",
+			method:  "getMe",
+			attempt: 1,
+			err:     apiError(429, 3),
+			retry:   true,
+			min:     3 * time.Second,
+			max:     3 * time.Second,
+		},
+		{
+			name:    "flood wait of sending",
+			method:  "sendMessage",
+			attempt: 1,
+			err:     apiError(429, 1),
+			retry:   true,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{name: "flood wait above max backoff", method: "getMe", attempt: 1, err: apiError(429, 11)},
+		{name: "last attempt", method: "getMe", attempt: 5, err: apiError(500, 0)},
+		{
+			name:    "server error",
+			method:  "getMe",
+			attempt: 1,
+			err:     apiError(502, 0),
+			retry:   true,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "backoff doubles",
+			method:  "getMe",
+			attempt: 3,
+			err:     apiError(500, 0),
+			retry:   true,
+			min:     200 * time.Millisecond,
+			max:     400 * time.Millisecond,
+		},
+		{
+			name:    "backoff is capped",
+			policy:  request.BackoffRetryPolicy{MaxAttempts: 100, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second},
+			method:  "getMe",
+			attempt: 70,
+			err:     apiError(500, 0),
+			retry:   true,
+			min:     500 * time.Millisecond,
+			max:     time.Second,
+		},
+		{name: "server error of sending", method: "sendMessage", attempt: 1, err: apiError(500, 0)},
+		{
+			name:    "server error of sending with RetryNonIdempotent",
+			policy:  request.BackoffRetryPolicy{MinBackoff: 100 * time.Millisecond, RetryNonIdempotent: true},
+			method:  "sendMessage",
+			attempt: 1,
+			err:     apiError(500, 0),
+			retry:   true,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{name: "bad request", method: "getMe", attempt: 1, err: apiError(400, 0)},
+		{name: "forbidden", method: "getMe", attempt: 1, err: apiError(403, 0)},
+		{name: "canceled", method: "getMe", attempt: 1, err: urlError(context.Canceled)},
+		{name: "deadline", method: "getMe", attempt: 1, err: urlError(context.DeadlineExceeded)},
+		{
+			name:    "connection closed",
+			method:  "getMe",
+			attempt: 1,
+			err:     urlError(io.EOF),
+			retry:   true,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "truncated response",
+			method:  "getMe",
+			attempt: 1,
+			err:     io.ErrUnexpectedEOF,
+			retry:   true,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "connection refused",
+			method:  "getMe",
+			attempt: 1,
+			err:     urlError(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}),
+			retry:   true,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "dns error",
+			method:  "getMe",
+			attempt: 1,
+			err:     urlError(&net.DNSError{Err: "no such host"}),
+			retry:   true,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "timeout",
+			method:  "getMe",
+			attempt: 1,
+			err:     urlError(timeoutError{}),
+			retry:   true,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "missing file",
+			method:  "setChatPhoto",
+			attempt: 1,
+			err:     urlError(&os.PathError{Op: "open", Path: "photo.jpg", Err: syscall.ENOENT}),
+		},
+		{name: "decoding error", method: "getMe", attempt: 1, err: &json.SyntaxError{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.policy
+			if p == (request.BackoffRetryPolicy{}) {
+				p = policy
+			}
+
+			delay, retry := p.Delay(tc.method, tc.attempt, tc.err)
+			if retry != tc.retry {
+				t.Fatalf("unexpected decision: got %v, want %v", retry, tc.retry)
+			}
+
+			if retry && (delay < tc.min || delay > tc.max) {
+				t.Errorf("unexpected delay: got %s, want in [%s, %s]", delay, tc.min, tc.max)
+			}
+		})
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	if _, retry := (request.NoRetry{}).Delay("getMe", 1, apiError(500, 0)); retry {
+		t.Error("NoRetry repeats the request")
+	}
+}
+
+var fastRetry = request.BackoffRetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Second}
+
+func TestRetryServerErrors(t *testing.T) {
+	server := telegramtest.NewServer()
+	defer server.Close()
+
+	api := server.API(request.WithRetryPolicy(fastRetry))
+
+	server.RespondError("getMe", http.StatusBadGateway, "Bad Gateway")
+	server.RespondError("getMe", http.StatusInternalServerError, "Internal Server Error")
+
+	if _, err := api.GetMe(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := len(server.CallsTo("getMe")); n != 3 {
+		t.Errorf("unexpected number of attempts: got %d, want 3", n)
+	}
+
+	server.Reset()
+	for i := 0; i < 3; i++ {
+		server.RespondError("getMe", http.StatusInternalServerError, "Internal Server Error")
+	}
+
+	var apiErr *request.APIError
+	if _, err := api.GetMe(context.Background()); !errors.As(err, &apiErr) || apiErr.ErrorCode != 500 {
+		t.Errorf("unexpected error after the last attempt: %v", err)
+	}
+
+	if n := len(server.CallsTo("getMe")); n != 3 {
+		t.Errorf("unexpected number of attempts: got %d, want 3", n)
+	}
+}
+
+func TestRetryDoesNotRepeatSending(t *testing.T) {
+	server := telegramtest.NewServer()
+	defer server.Close()
+
+	api := server.API(request.WithRetryPolicy(fastRetry))
+
+	server.RespondError("sendMessage", http.StatusInternalServerError, "Internal Server Error")
+
+	_, err := api.SendMessage(context.Background(), models.MessageRequest{
+		MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+		Text:               "hello",
+	})
+	if err == nil {
+		t.Fatal("the request is repeated")
+	}
+
+	if n := len(server.CallsTo("sendMessage")); n != 1 {
+		t.Errorf("unexpected number of attempts: got %d, want 1", n)
+	}
+}
+
+func TestRetryFloodWait(t *testing.T) {
+	server := telegramtest.NewServer()
+	defer server.Close()
+
+	api := server.API(request.WithRetryPolicy(fastRetry))
+
+	server.RespondFloodWait("sendMessage", 1)
+
+	started := time.Now()
+	_, err := api.SendMessage(context.Background(), models.MessageRequest{
+		MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+		Text:               "hello",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(started); elapsed < time.Second {
+		t.Errorf("retry_after isn't respected: the request is repeated in %s", elapsed)
+	}
+
+	if n := len(server.CallsTo("sendMessage")); n != 2 {
+		t.Errorf("unexpected number of attempts: got %d, want 2", n)
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	server := telegramtest.NewServer()
+	defer server.Close()
+
+	api := server.API(request.WithRetryPolicy(fastRetry))
+
+	server.RespondFloodWait("getMe", 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	started := time.Now()
+	_, err := api.GetMe(ctx)
+	if !errors.Is(err, request.ErrFloodWait) {
+		t.Errorf("unexpected error: got %v, want %v", err, request.ErrFloodWait)
+	}
+
+	// the retry can't happen before the deadline, so the error is returned right away
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("the request waited for %s", elapsed)
+	}
+
+	if n := len(server.CallsTo("getMe")); n != 1 {
+		t.Errorf("unexpected number of attempts: got %d, want 1", n)
+	}
+}
+
+func TestRetryUploads(t *testing.T) {
+	cases := []struct {
+		name     string
+		document func() *models.InputFile
+		attempts int
+	}{
+		{
+			name: "bytes", document: func() *models.InputFile { return models.FromBytes("a.txt", []byte("data")) }, attempts: 2,
+		},
+		{
+			name:     "reader",
+			document: func() *models.InputFile { return models.FromReader("a.txt", bytes.NewReader([]byte("data"))) },
+			attempts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := telegramtest.NewServer()
+			defer server.Close()
+
+			policy := fastRetry
+			policy.RetryNonIdempotent = true
+			api := server.API(request.WithRetryPolicy(policy))
+
+			server.RespondError("sendDocument", http.StatusInternalServerError, "Internal Server Error")
+
+			_, _ = api.SendDocument(context.Background(), models.DocumentMessageRequest{
+				MessageRequestBase: models.MessageRequestBase{ChatID: "1"},
+				Document:           tc.document(),
+			})
+
+			// a reader can't be read again, so the upload isn't repeated
+			if n := len(server.CallsTo("sendDocument")); n != tc.attempts {
+				t.Errorf("unexpected number of attempts: got %d, want %d", n, tc.attempts)
+			}
+		})
+	}
+}
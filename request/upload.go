@@ -0,0 +1,132 @@
+package request
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+type progressKey struct{}
+
+// ProgressFunc Receives the number of bytes of the files sent so far and their total size, or -1 if the size
+// of any file is unknown.
+type ProgressFunc func(sent, total int64)
+
+// WithProgress Returns a copy of ctx which reports the upload progress of multipart requests to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func progressFromContext(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+
+	return fn
+}
+
+// upload Streams a multipart/form-data body through a pipe, so files are never buffered in memory.
+type upload struct {
+	boundary string
+	params   map[string]string
+	files    map[string]*models.InputFile
+	progress ProgressFunc
+	total    int64
+}
+
+func newUpload(boundary string, params map[string]string, files map[string]*models.InputFile, progress ProgressFunc) *upload {
+	var total int64
+	for _, file := range files {
+		size := file.Size()
+		if size < 0 {
+			total = -1
+			break
+		}
+		total += size
+	}
+
+	return &upload{
+		boundary: boundary,
+		params:   params,
+		files:    files,
+		progress: progress,
+		total:    total,
+	}
+}
+
+// reusable Reports whether the body can be produced once again, e.g. to retry the request.
+func (u *upload) reusable() bool {
+	for _, file := range u.files {
+		if !file.CanReopen() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// body Starts writing the form in the background. The writer stops as soon as the returned reader is closed.
+func (u *upload) body() io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		_ = pw.CloseWithError(u.write(pw))
+	}()
+
+	return pr
+}
+
+func (u *upload) write(w io.Writer) error {
+	writer := multipart.NewWriter(w)
+	err := writer.SetBoundary(u.boundary)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range u.params {
+		err = writer.WriteField(name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	counter := &progressWriter{progress: u.progress, total: u.total}
+	for name, file := range u.files {
+		part, err := writer.CreateFormFile(name, file.Name())
+		if err != nil {
+			return err
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		counter.w = part
+		_, err = io.Copy(counter, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+type progressWriter struct {
+	w        io.Writer
+	progress ProgressFunc
+	sent     int64
+	total    int64
+}
+
+func (p *progressWriter) Write(data []byte) (int, error) {
+	n, err := p.w.Write(data)
+	p.sent += int64(n)
+
+	if p.progress != nil {
+		p.progress(p.sent, p.total)
+	}
+
+	return n, err
+}
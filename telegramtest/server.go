@@ -0,0 +1,396 @@
+package telegramtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/request"
+)
+
+const (
+	DefaultToken = "123456:test-token"
+
+	maxMultipartMemory = 32 << 20
+)
+
+// Call A request received by the fake server.
+type Call struct {
+	// Name of the called method, e.g. sendMessage
+	Method string
+
+	// Decoded parameters. JSON requests are decoded as is (numbers become float64), values of multipart requests
+	// are kept as strings unless they contain JSON objects or arrays
+	Params map[string]interface{}
+
+	// Content of the files uploaded using multipart/form-data by their field names
+	Files map[string][]byte
+
+	// Result of the successful response, e.g. the sent Message. It is nil for errors and for calls which haven't been
+	// answered yet, e.g. a pending getUpdates
+	Result interface{}
+}
+
+// Response A scripted response of the fake server.
+type Response struct {
+	// Result of the successful request
+	Result interface{}
+
+	// Code of the error. The response is successful if it is zero
+	ErrorCode int
+
+	// Description of the error
+	Description string
+
+	// Information about why the request was unsuccessful
+	Parameters *models.ResponseParameters
+}
+
+// Server An in-process fake of the Bot API server. It records incoming method calls, replies with scripted responses
+// and serves updates to getUpdates. Methods without scripted responses return a Message for sending methods,
+// a bot User for getMe and True for everything else.
+type Server struct {
+	*httptest.Server
+
+	// Bot token the server accepts. Requests with other tokens fail with 401 Unauthorized
+	Token string
+
+	// The bot returned by getMe
+	Bot models.User
+
+	lock      sync.Mutex
+	calls     []Call
+	resets    int
+	once      map[string][]Response
+	always    map[string]Response
+	files     map[string][]byte
+	messageID int64
+	updates   *updateQueue
+}
+
+func NewServer() *Server {
+	s := &Server{
+		Token: DefaultToken,
+		Bot: models.User{
+			ID:        123456,
+			IsBot:     true,
+			FirstName: "Test",
+			Username:  "test_bot",
+		},
+		once:    make(map[string][]Response),
+		always:  make(map[string]Response),
+		files:   make(map[string][]byte),
+		updates: newUpdateQueue(),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// API Returns the API client connected to the server.
+func (s *Server) API(opts ...request.Option) *telegram.API {
+	opts = append([]request.Option{request.WithBaseURL(s.URL)}, opts...)
+
+	return telegram.NewAPIWithClient(s.Token, s.Client(), opts...)
+}
+
+// Calls Returns all recorded calls in the order of arrival.
+func (s *Server) Calls() []Call {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	calls := make([]Call, len(s.calls))
+	copy(calls, s.calls)
+
+	return calls
+}
+
+// CallsTo Returns recorded calls of the method.
+func (s *Server) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range s.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// LastCall Returns the last recorded call of the method.
+func (s *Server) LastCall(method string) (Call, bool) {
+	calls := s.CallsTo(method)
+	if len(calls) == 0 {
+		return Call{}, false
+	}
+
+	return calls[len(calls)-1], true
+}
+
+// Reset Forgets recorded calls and scripted responses.
+func (s *Server) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.calls = nil
+	s.resets++
+	s.once = make(map[string][]Response)
+	s.always = make(map[string]Response)
+}
+
+// Respond Makes every following call of the method return the result.
+func (s *Server) Respond(method string, result interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.always[method] = Response{Result: result}
+}
+
+// Enqueue Makes the next calls of the method return the responses one by one. Scripted responses take
+// precedence over the ones set by Respond.
+func (s *Server) Enqueue(method string, responses ...Response) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.once[method] = append(s.once[method], responses...)
+}
+
+// RespondOnce Makes the next call of the method return the result.
+func (s *Server) RespondOnce(method string, result interface{}) {
+	s.Enqueue(method, Response{Result: result})
+}
+
+// RespondError Makes the next call of the method fail with the error.
+func (s *Server) RespondError(method string, code int, description string) {
+	s.Enqueue(method, Response{ErrorCode: code, Description: description})
+}
+
+// RespondFloodWait Makes the next call of the method fail with 429 Too Many Requests and retry_after.
+func (s *Server) RespondFloodWait(method string, retryAfter int) {
+	s.Enqueue(method, Response{
+		ErrorCode:   http.StatusTooManyRequests,
+		Description: "Too Many Requests: retry after " + strconv.Itoa(retryAfter),
+		Parameters:  &models.ResponseParameters{RetryAfter: retryAfter},
+	})
+}
+
+// AddFile Makes the file available for download by its file_path.
+func (s *Server) AddFile(filePath string, data []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.files[filePath] = data
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	filePrefix := "/file/bot" + s.Token + "/"
+	if strings.HasPrefix(r.URL.Path, filePrefix) {
+		s.serveFile(w, strings.TrimPrefix(r.URL.Path, filePrefix))
+		return
+	}
+
+	methodPrefix := "/bot" + s.Token + "/"
+	if !strings.HasPrefix(r.URL.Path, methodPrefix) {
+		writeResponse(w, Response{ErrorCode: http.StatusUnauthorized, Description: "Unauthorized"})
+		return
+	}
+
+	call, err := decodeCall(strings.TrimPrefix(r.URL.Path, methodPrefix), r)
+	if err != nil {
+		writeResponse(w, Response{ErrorCode: http.StatusBadRequest, Description: "Bad Request: " + err.Error()})
+		return
+	}
+
+	s.lock.Lock()
+	s.calls = append(s.calls, call)
+	index, resets := len(s.calls)-1, s.resets
+	s.lock.Unlock()
+
+	response, ok := s.scripted(call.Method)
+	if !ok {
+		response = s.defaultResponse(r, call)
+	}
+
+	s.lock.Lock()
+	if s.resets == resets && response.ErrorCode == 0 {
+		s.calls[index].Result = response.Result
+	}
+	s.lock.Unlock()
+
+	writeResponse(w, response)
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, filePath string) {
+	s.lock.Lock()
+	data, ok := s.files[filePath]
+	s.lock.Unlock()
+
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+
+	_, _ = w.Write(data)
+}
+
+func (s *Server) scripted(method string) (Response, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if queue := s.once[method]; len(queue) > 0 {
+		s.once[method] = queue[1:]
+
+		return queue[0], true
+	}
+
+	response, ok := s.always[method]
+
+	return response, ok
+}
+
+func (s *Server) defaultResponse(r *http.Request, call Call) Response {
+	switch {
+	case call.Method == "getMe":
+		return Response{Result: s.Bot}
+	case call.Method == "getUpdates":
+		return Response{Result: s.updates.get(r.Context(), call.Params)}
+	case strings.HasPrefix(call.Method, "send") || call.Method == "forwardMessage":
+		return Response{Result: s.newMessage(call)}
+	default:
+	}
+
+	return Response{Result: true}
+}
+
+func (s *Server) newMessage(call Call) models.Message {
+	s.lock.Lock()
+	s.messageID++
+	id := s.messageID
+	s.lock.Unlock()
+
+	bot := s.Bot
+	msg := models.Message{
+		ID:        id,
+		From:      &bot,
+		Timestamp: time.Now().Unix(),
+		Chat:      &models.Chat{Type: models.ChatTypePrivate},
+	}
+
+	switch chatID := call.Params["chat_id"].(type) {
+	case float64:
+		msg.Chat.ID = int64(chatID)
+	case string:
+		if strings.HasPrefix(chatID, "@") {
+			msg.Chat.Type = models.ChatTypeChannel
+			msg.Chat.Username = strings.TrimPrefix(chatID, "@")
+		} else {
+			msg.Chat.ID, _ = strconv.ParseInt(chatID, 10, 64)
+		}
+	default:
+	}
+
+	if msg.Chat.ID < 0 {
+		msg.Chat.Type = models.ChatTypeSuperGroup
+	}
+
+	if text, ok := call.Params["text"].(string); ok {
+		msg.Text = text
+	}
+
+	return msg
+}
+
+func decodeCall(method string, r *http.Request) (Call, error) {
+	call := Call{
+		Method: method,
+		Params: make(map[string]interface{}),
+		Files:  make(map[string][]byte),
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "multipart/form-data":
+		err := r.ParseMultipartForm(maxMultipartMemory)
+		if err != nil {
+			return call, err
+		}
+
+		for name, values := range r.MultipartForm.Value {
+			call.Params[name] = decodeFormValue(values[0])
+		}
+
+		for name, headers := range r.MultipartForm.File {
+			f, err := headers[0].Open()
+			if err != nil {
+				return call, err
+			}
+
+			data, err := ioutil.ReadAll(f)
+			_ = f.Close()
+			if err != nil {
+				return call, err
+			}
+
+			call.Files[name] = data
+		}
+	default:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return call, err
+		}
+
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '{' {
+			err = json.Unmarshal(body, &call.Params)
+			if err != nil {
+				return call, err
+			}
+		}
+	}
+
+	return call, nil
+}
+
+func decodeFormValue(value string) interface{} {
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			return decoded
+		}
+	}
+
+	return value
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	body := map[string]interface{}{
+		"ok": response.ErrorCode == 0,
+	}
+
+	status := http.StatusOK
+	if response.ErrorCode == 0 {
+		body["result"] = response.Result
+	} else {
+		status = response.ErrorCode
+		body["error_code"] = response.ErrorCode
+		body["description"] = response.Description
+		if response.Parameters != nil {
+			body["parameters"] = response.Parameters
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
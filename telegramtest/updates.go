@@ -0,0 +1,178 @@
+package telegramtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/s-larionov/telegram-api/models"
+)
+
+const defaultUpdatesLimit = 100
+
+type updateQueue struct {
+	lock    sync.Mutex
+	updates []models.Update
+	lastID  int64
+	notify  chan struct{}
+	closed  chan struct{}
+}
+
+func newUpdateQueue() *updateQueue {
+	return &updateQueue{
+		notify: make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+}
+
+// nextID Assigns the identifier to the update if it doesn't have one.
+func (q *updateQueue) nextID(update models.Update) models.Update {
+	if update.ID == 0 {
+		update.ID = q.lastID + 1
+	}
+
+	if update.ID > q.lastID {
+		q.lastID = update.ID
+	}
+
+	return update
+}
+
+func (q *updateQueue) push(updates ...models.Update) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for _, update := range updates {
+		q.updates = append(q.updates, q.nextID(update))
+	}
+
+	close(q.notify)
+	q.notify = make(chan struct{})
+}
+
+func (q *updateQueue) assignID(update models.Update) models.Update {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.nextID(update)
+}
+
+// get Confirms updates before the offset and returns the pending ones. Like the real server it holds the request
+// until there are updates or the long polling timeout expires.
+func (q *updateQueue) get(ctx context.Context, params map[string]interface{}) []models.Update {
+	offset, _ := params["offset"].(float64)
+	limit, _ := params["limit"].(float64)
+	timeout, _ := params["timeout"].(float64)
+
+	if limit <= 0 || limit > defaultUpdatesLimit {
+		limit = defaultUpdatesLimit
+	}
+
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
+	for {
+		q.lock.Lock()
+		q.confirm(int64(offset))
+		pending := q.updates
+		if len(pending) > int(limit) {
+			pending = pending[:int(limit)]
+		}
+		updates := make([]models.Update, len(pending))
+		copy(updates, pending)
+		notify := q.notify
+		q.lock.Unlock()
+
+		if len(updates) > 0 {
+			return updates
+		}
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return updates
+		case <-ctx.Done():
+			return updates
+		case <-q.closed:
+			return updates
+		}
+	}
+}
+
+func (q *updateQueue) confirm(offset int64) {
+	i := 0
+	for i < len(q.updates) && q.updates[i].ID < offset {
+		i++
+	}
+
+	q.updates = q.updates[i:]
+}
+
+func (q *updateQueue) pending() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return len(q.updates)
+}
+
+func (q *updateQueue) close() {
+	select {
+	case <-q.closed:
+	default:
+		close(q.closed)
+	}
+}
+
+// PushUpdates Adds the updates to the getUpdates queue. Updates without identifiers get sequential ones.
+func (s *Server) PushUpdates(updates ...models.Update) {
+	s.updates.push(updates...)
+}
+
+// PendingUpdates Returns the number of updates which haven't been confirmed by getUpdates yet.
+func (s *Server) PendingUpdates() int {
+	return s.updates.pending()
+}
+
+// PostUpdate Delivers the update to the webhook handler the same way the Bot API does and returns the recorded response.
+// The update gets a sequential identifier if it doesn't have one. The request goes to the path and carries
+// the secret token of the last setWebhook call.
+func (s *Server) PostUpdate(handler http.Handler, update models.Update) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(s.updates.assignID(update))
+	if err != nil {
+		return nil, err
+	}
+
+	target := "/"
+	var secretToken string
+	if call, ok := s.LastCall("setWebhook"); ok {
+		if webhookURL, ok := call.Params["url"].(string); ok {
+			if u, err := url.Parse(webhookURL); err == nil && u.Path != "" {
+				target = u.Path
+			}
+		}
+
+		secretToken, _ = call.Params["secret_token"].(string)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if secretToken != "" {
+		r.Header.Set("X-Telegram-Bot-Api-Secret-Token", secretToken)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w, nil
+}
+
+// Close Releases pending getUpdates requests and shuts down the server.
+func (s *Server) Close() {
+	s.updates.close()
+	s.Server.Close()
+}
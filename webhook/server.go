@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/models"
+)
+
+const (
+	// SecretTokenHeader The header containing the secret token set by SetWebhook
+	SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	defaultAddr            = ":8443"
+	defaultPath            = "/"
+	defaultMaxBodySize     = 1 << 20
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+var ErrServerStarted = errors.New("webhook server is already started")
+
+// Options Settings of the webhook server.
+type Options struct {
+	// TCP address to listen on. Defaults to ":8443".
+	Addr string
+
+	// Public HTTPS url of the webhook. If it is set, the webhook is registered using setWebhook on start.
+	URL string
+
+	// Path to accept updates on. Use a secret path, e.g. the bot token, to make sure that requests come from Telegram.
+	// Defaults to the path of URL, or to "/" if URL is empty.
+	Path string
+
+	// Secret token expected in the X-Telegram-Bot-Api-Secret-Token header. Requests without the token are rejected.
+	// The token is passed to setWebhook when the webhook is registered.
+	SecretToken string
+
+	// Maximum size of the request body in bytes. Defaults to 1MB.
+	MaxBodySize int64
+
+	// Certificate and matching private key files to serve HTTPS. Plain HTTP is served if they aren't set,
+	// e.g. behind a reverse proxy terminating TLS.
+	CertFile string
+	KeyFile  string
+
+	// Public key certificate uploaded by setWebhook, so that a self-signed certificate can be checked.
+	Certificate *models.InputFile
+
+	// Maximum allowed number of simultaneous HTTPS connections to the webhook for update delivery, 1-100.
+	MaxConnections int
+
+	// A list of the update types you want your bot to receive.
+	AllowedUpdates []models.UpdateType
+
+	// Remove the webhook using deleteWebhook on shutdown.
+	DeleteOnShutdown bool
+
+	// Time to wait for in-flight updates to be processed on shutdown. Defaults to 10 seconds.
+	ShutdownTimeout time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Addr == "" {
+		o.Addr = defaultAddr
+	}
+
+	if o.Path == "" && o.URL != "" {
+		if u, err := url.Parse(o.URL); err == nil {
+			o.Path = u.Path
+		}
+	}
+
+	if o.Path == "" {
+		o.Path = defaultPath
+	}
+
+	if o.MaxBodySize <= 0 {
+		o.MaxBodySize = defaultMaxBodySize
+	}
+
+	if o.ShutdownTimeout <= 0 {
+		o.ShutdownTimeout = defaultShutdownTimeout
+	}
+
+	return o
+}
+
+// Server Receives updates using an outgoing webhook and emits them to the subscribers of the API.
+type Server struct {
+	api     *telegram.API
+	opts    Options
+	lock    sync.Mutex
+	started bool
+}
+
+func NewServer(api *telegram.API, opts Options) *Server {
+	return &Server{
+		api:  api,
+		opts: opts.withDefaults(),
+	}
+}
+
+// Handler Returns the handler verifying webhook requests. Verified requests are passed to API.WebhookHandler.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+// Run Listens on the address from the options and serves webhook requests until the context is canceled.
+// See Serve for details.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve Serves webhook requests on the listener until the context is canceled. The webhook is registered using
+// setWebhook if the URL is set. When the context is done, the server stops accepting requests, waits for in-flight
+// updates to be processed and removes the webhook if DeleteOnShutdown is set. Returns ctx.Err() after a graceful
+// shutdown or if the context is canceled before the webhook is set.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	s.lock.Lock()
+	if s.started {
+		s.lock.Unlock()
+		_ = listener.Close()
+
+		return ErrServerStarted
+	}
+	s.started = true
+	s.lock.Unlock()
+
+	srv := &http.Server{
+		Handler: s.Handler(),
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		if s.opts.CertFile != "" || s.opts.KeyFile != "" {
+			served <- srv.ServeTLS(listener, s.opts.CertFile, s.opts.KeyFile)
+		} else {
+			served <- srv.Serve(listener)
+		}
+	}()
+
+	if s.opts.URL != "" {
+		err := s.api.SetWebhook(ctx, models.WebhookRequest{
+			URL:            s.opts.URL,
+			Certificate:    s.opts.Certificate,
+			MaxConnections: s.opts.MaxConnections,
+			AllowedUpdates: s.opts.AllowedUpdates,
+			SecretToken:    s.opts.SecretToken,
+		})
+		if err != nil {
+			_ = srv.Close()
+			<-served
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return err
+		}
+
+		log.WithField("url", s.opts.URL).Info("webhook is set")
+	}
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	return s.shutdown(ctx, srv)
+}
+
+func (s *Server) shutdown(ctx context.Context, srv *http.Server) error {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.opts.ShutdownTimeout)
+	defer cancel()
+
+	err := srv.Shutdown(shutdownCtx)
+	if err != nil {
+		log.WithError(err).Error("unable to drain webhook requests")
+		_ = srv.Close()
+
+		return err
+	}
+
+	if s.opts.DeleteOnShutdown {
+		err = s.api.DeleteWebhook(shutdownCtx)
+		if err != nil {
+			return err
+		}
+
+		log.Info("webhook is deleted")
+	}
+
+	return ctx.Err()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.opts.Path {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.opts.SecretToken != "" {
+		token := r.Header.Get(SecretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.SecretToken)) != 1 {
+			log.WithField("remote_addr", r.RemoteAddr).Warn("webhook request with invalid secret token")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if r.ContentLength > s.opts.MaxBodySize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, s.opts.MaxBodySize+1))
+	if err != nil {
+		log.WithError(err).Error("unable to read webhook request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if int64(len(body)) > s.opts.MaxBodySize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	s.api.WebhookHandler(w, r)
+}
@@ -0,0 +1,377 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s-larionov/telegram-api"
+	"github.com/s-larionov/telegram-api/events"
+	"github.com/s-larionov/telegram-api/models"
+	"github.com/s-larionov/telegram-api/telegramtest"
+	"github.com/s-larionov/telegram-api/webhook"
+)
+
+const (
+	webhookURL  = "https://example.com/hook/secret-path"
+	secretToken = "secret-token"
+)
+
+type serverTest struct {
+	telegram *telegramtest.Server
+	api      *telegram.API
+	server   *webhook.Server
+	url      string
+	cancel   context.CancelFunc
+	done     chan error
+}
+
+// serve Starts the webhook server on a random local port and waits until the webhook is set.
+func serve(t *testing.T, opts webhook.Options) *serverTest {
+	t.Helper()
+
+	tg := telegramtest.NewServer()
+	t.Cleanup(tg.Close)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	api := tg.API()
+	st := &serverTest{
+		telegram: tg,
+		api:      api,
+		server:   webhook.NewServer(api, opts),
+		url:      "http://" + listener.Addr().String() + "/hook/secret-path",
+		cancel:   cancel,
+		done:     make(chan error, 1),
+	}
+
+	go func() {
+		st.done <- st.server.Serve(ctx, listener)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := tg.LastCall("setWebhook"); ok {
+			// lets Serve receive the response
+			time.Sleep(20 * time.Millisecond)
+
+			return st
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("the webhook isn't set")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (st *serverTest) post(t *testing.T, target, token string, body io.Reader) int {
+	t.Helper()
+
+	status, err := postUpdate(target, token, body)
+	if err != nil {
+		t.Fatalf("unable to post the request: %v", err)
+	}
+
+	return status
+}
+
+func postUpdate(target, token string, body io.Reader) (int, error) {
+	r, err := http.NewRequest(http.MethodPost, target, body)
+	if err != nil {
+		return 0, err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		r.Header.Set(webhook.SecretTokenHeader, token)
+	}
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return 0, err
+	}
+
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+func (st *serverTest) stop(t *testing.T) error {
+	t.Helper()
+
+	st.cancel()
+
+	select {
+	case err := <-st.done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server didn't stop")
+	}
+
+	return nil
+}
+
+func updateBody(t *testing.T, id int64) io.Reader {
+	t.Helper()
+
+	body, err := json.Marshal(models.Update{ID: id, Message: &models.Message{ID: id, Text: "hello"}})
+	if err != nil {
+		t.Fatalf("unable to encode the update: %v", err)
+	}
+
+	return bytes.NewReader(body)
+}
+
+func TestServerSetsAndDeletesWebhook(t *testing.T) {
+	st := serve(t, webhook.Options{
+		URL:              webhookURL,
+		SecretToken:      secretToken,
+		MaxConnections:   10,
+		AllowedUpdates:   []models.UpdateType{models.UpdateTypeMessage},
+		DeleteOnShutdown: true,
+	})
+
+	call, _ := st.telegram.LastCall("setWebhook")
+	if call.Params["url"] != webhookURL || call.Params["secret_token"] != secretToken ||
+		call.Params["max_connections"] != float64(10) {
+		t.Errorf("unexpected setWebhook parameters: %v", call.Params)
+	}
+
+	if err := st.stop(t); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	if _, ok := st.telegram.LastCall("deleteWebhook"); !ok {
+		t.Error("the webhook isn't deleted on shutdown")
+	}
+}
+
+func TestServerVerifiesRequests(t *testing.T) {
+	st := serve(t, webhook.Options{URL: webhookURL, SecretToken: secretToken, MaxBodySize: 512})
+	updates := st.api.SubscribeAll(events.SubscribeOptions{Overflow: events.OverflowSpill}).Updates()
+
+	large := `{"update_id":1,"message":{"message_id":1,"text":"` + strings.Repeat("a", 1024) + `"}}`
+
+	cases := []struct {
+		name   string
+		target string
+		token  string
+		body   io.Reader
+		status int
+	}{
+		{name: "valid", target: st.url, token: secretToken, body: updateBody(t, 1), status: http.StatusOK},
+		{name: "wrong token", target: st.url, token: "wrong", body: updateBody(t, 2), status: http.StatusUnauthorized},
+		{name: "missing token", target: st.url, body: updateBody(t, 3), status: http.StatusUnauthorized},
+		{
+			name:   "wrong path",
+			target: st.url + "-other",
+			token:  secretToken,
+			body:   updateBody(t, 4),
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "large body",
+			target: st.url,
+			token:  secretToken,
+			body:   strings.NewReader(large),
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			// without Content-Length the body is limited while reading
+			name:   "large chunked body",
+			target: st.url,
+			token:  secretToken,
+			body:   ioutil.NopCloser(strings.NewReader(large)),
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "invalid body",
+			target: st.url,
+			token:  secretToken,
+			body:   strings.NewReader("{"),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status := st.post(t, tc.target, tc.token, tc.body); status != tc.status {
+				t.Errorf("unexpected status: got %d, want %d", status, tc.status)
+			}
+		})
+	}
+
+	resp, err := http.Get(st.url)
+	if err != nil {
+		t.Fatalf("unable to send the request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodPost {
+		t.Errorf("unexpected response to GET: %d, Allow: %q", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+
+	if err := st.stop(t); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case u := <-updates:
+		if u.ID != 1 {
+			t.Errorf("unexpected update: got %d, want 1", u.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the valid update isn't received")
+	}
+
+	select {
+	case u := <-updates:
+		t.Errorf("the rejected update %d is received", u.ID)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestServerHandler(t *testing.T) {
+	tg := telegramtest.NewServer()
+	defer tg.Close()
+
+	api := tg.API()
+	server := webhook.NewServer(api, webhook.Options{URL: webhookURL, SecretToken: secretToken})
+
+	// PostUpdate uses the path and the secret token of the last setWebhook call
+	err := api.SetWebhook(context.Background(), models.WebhookRequest{URL: webhookURL, SecretToken: secretToken})
+	if err != nil {
+		t.Fatalf("unable to set the webhook: %v", err)
+	}
+
+	w, err := tg.PostUpdate(server.Handler(), models.Update{Message: &models.Message{Text: "hello"}})
+	if err != nil {
+		t.Fatalf("unable to post the update: %v", err)
+	}
+
+	// nobody is subscribed, so Telegram has to deliver the update again
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status without subscribers: got %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	updates := api.SubscribeAll(events.SubscribeOptions{}).Updates()
+
+	w, err = tg.PostUpdate(server.Handler(), models.Update{Message: &models.Message{Text: "hello"}})
+	if err != nil {
+		t.Fatalf("unable to post the update: %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("unexpected status: got %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if u := <-updates; u.Message == nil || u.Message.Text != "hello" {
+		t.Errorf("unexpected update: %+v", u)
+	}
+}
+
+func TestServerDrainsRequestsOnShutdown(t *testing.T) {
+	st := serve(t, webhook.Options{URL: webhookURL, ShutdownTimeout: 5 * time.Second})
+
+	// the second update blocks in Emit until the first one is read
+	subscription := st.api.SubscribeAll(events.SubscribeOptions{BufferSize: 1})
+
+	if status := st.post(t, st.url, "", updateBody(t, 1)); status != http.StatusOK {
+		t.Fatalf("unexpected status: got %d, want %d", status, http.StatusOK)
+	}
+
+	body := updateBody(t, 2)
+	inFlight := make(chan int, 1)
+	go func() {
+		status, err := postUpdate(st.url, "", body)
+		if err != nil {
+			t.Errorf("unable to post the request: %v", err)
+		}
+
+		inFlight <- status
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	st.cancel()
+
+	select {
+	case err := <-st.done:
+		t.Fatalf("the server stopped before the in-flight request was processed: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for _, id := range []int64{1, 2} {
+		if u := <-subscription.Updates(); u.ID != id {
+			t.Errorf("unexpected update: got %d, want %d", u.ID, id)
+		}
+	}
+
+	if status := <-inFlight; status != http.StatusOK {
+		t.Errorf("unexpected status of the in-flight request: got %d, want %d", status, http.StatusOK)
+	}
+
+	select {
+	case err := <-st.done:
+		if err != context.Canceled {
+			t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server didn't stop")
+	}
+
+	if _, ok := st.telegram.LastCall("deleteWebhook"); ok {
+		t.Error("the webhook is deleted without DeleteOnShutdown")
+	}
+}
+
+func TestServerServeTwice(t *testing.T) {
+	st := serve(t, webhook.Options{URL: webhookURL})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	if err := st.server.Serve(context.Background(), listener); !errors.Is(err, webhook.ErrServerStarted) {
+		t.Errorf("unexpected error: got %v, want %v", err, webhook.ErrServerStarted)
+	}
+
+	if err := st.stop(t); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestServerCanceledBeforeWebhookIsSet(t *testing.T) {
+	tg := telegramtest.NewServer()
+	defer tg.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	server := webhook.NewServer(tg.API(), webhook.Options{URL: webhookURL})
+	if err := server.Serve(ctx, listener); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+}